@@ -1,18 +1,32 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
 	"log/slog"
+	"os"
 
 	"github.com/henderiw/logger/log"
 	"github.com/kform-dev/kform-plugin/kfprotov1"
 	"github.com/kform-dev/kform-plugin/kfprotov1/kfserver1"
 	"github.com/kform-dev/kform-sdk-go/pkg/schema"
 	"github.com/kform-providers/kubernetes/provider"
+	"github.com/kform-providers/kubernetes/provider/kstatus/statuscmd"
 )
 
 const providerName = "registry.fkorm.io/kform/kubernetes"
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == statuscmd.Name {
+		if err := statuscmd.Run(os.Args[2:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+			if !errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			os.Exit(1)
+		}
+		return
+	}
 
 	log := log.NewLogger(&log.HandlerOptions{Name: "provider-kubernetes-logger", AddSource: false})
 	slog.SetDefault(log)
@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var (
+	selfSubjectAccessReviewGVR = authorizationv1.SchemeGroupVersion.WithResource("selfsubjectaccessreviews")
+	selfSubjectRulesReviewGVR  = authorizationv1.SchemeGroupVersion.WithResource("selfsubjectrulesreviews")
+)
+
+// reviewAccess validates if the identity of the client is allowed to perform the check
+// using a SelfSubjectAccessReview. Results are cached for the lifetime of the client.
+func (r *Client) reviewAccess(ctx context.Context, check v1alpha1.AccessCheck) (*v1alpha1.AccessCheckResult, error) {
+	r.m.Lock()
+	result, ok := r.accessReviews[check]
+	r.m.Unlock()
+	if ok {
+		return result, nil
+	}
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: authorizationv1.SchemeGroupVersion.String(),
+			Kind:       "SelfSubjectAccessReview",
+		},
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   check.Namespace,
+				Verb:        check.Verb,
+				Group:       check.Group,
+				Resource:    check.Resource,
+				Subresource: check.Subresource,
+				Name:        check.Name,
+			},
+		},
+	}
+	if err := r.createReview(ctx, selfSubjectAccessReviewGVR, review); err != nil {
+		return nil, err
+	}
+	result = &v1alpha1.AccessCheckResult{
+		AccessCheck: check,
+		Allowed:     review.Status.Allowed,
+		Denied:      review.Status.Denied,
+		Reason:      review.Status.Reason,
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.accessReviews[check] = result
+	return result, nil
+}
+
+// reviewRules returns the rules of the identity of the client in the namespace
+// using a SelfSubjectRulesReview.
+func (r *Client) reviewRules(ctx context.Context, namespace string) (*v1alpha1.AccessRules, error) {
+	review := &authorizationv1.SelfSubjectRulesReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: authorizationv1.SchemeGroupVersion.String(),
+			Kind:       "SelfSubjectRulesReview",
+		},
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{
+			Namespace: namespace,
+		},
+	}
+	if err := r.createReview(ctx, selfSubjectRulesReviewGVR, review); err != nil {
+		return nil, err
+	}
+	return &v1alpha1.AccessRules{
+		Namespace:        namespace,
+		ResourceRules:    review.Status.ResourceRules,
+		NonResourceRules: review.Status.NonResourceRules,
+		Incomplete:       review.Status.Incomplete,
+		EvaluationError:  review.Status.EvaluationError,
+	}, nil
+}
+
+// createReview creates the review object and updates the review with the response
+// of the api server
+func (r *Client) createReview(ctx context.Context, gvr schema.GroupVersionResource, review runtime.Object) error {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(review)
+	if err != nil {
+		return err
+	}
+	newObj, err := r.dc.Resource(gvr).Create(ctx, &unstructured.Unstructured{Object: obj}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("cannot create %s: %w", gvr.Resource, err)
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(newObj.UnstructuredContent(), review)
+}
+
+// preflightAccess validates the identity of the client is allowed to perform the verbs
+// on the resource. It is a noop when preflight access reviews are not enabled.
+func (r *Client) preflightAccess(ctx context.Context, u *unstructured.Unstructured, verbs ...string) error {
+	if !r.preflightAccessReview {
+		return nil
+	}
+	m, err := r.getMapping(u)
+	if err != nil {
+		return err
+	}
+	namespace := ""
+	if m.Scope == meta.RESTScopeNamespace {
+		namespace = u.GetNamespace()
+	}
+	for _, verb := range verbs {
+		check := v1alpha1.AccessCheck{
+			Verb:      verb,
+			Group:     m.Resource.Group,
+			Resource:  m.Resource.Resource,
+			Namespace: namespace,
+		}
+		// the name is not known to the authorizer for create
+		if verb != "create" {
+			check.Name = u.GetName()
+		}
+		result, err := r.reviewAccess(ctx, check)
+		if err != nil {
+			return err
+		}
+		if !result.Allowed {
+			return fmt.Errorf("preflight access review failed: not allowed to %s %s %s, reason: %q",
+				verb, m.Resource.GroupResource().String(), types.NamespacedName{Namespace: namespace, Name: u.GetName()}.String(), result.Reason)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	sdkschema "github.com/kform-dev/kform-sdk-go/pkg/schema"
+	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newAccessReviewClient returns a Client whose access reviews allow all verbs
+// except the denied verbs, the reviewed checks are recorded. The rules reviews
+// return the resourceRules, the rules of kube-system are incomplete.
+func newAccessReviewClient(t *testing.T, denied ...string) (*Client, *[]v1alpha1.AccessCheck) {
+	c := newTestClient(map[schema.GroupVersionKind]meta.RESTScope{
+		configMapGVK: meta.RESTScopeNamespace,
+		namespaceGVK: meta.RESTScopeRoot,
+	})
+	c.preflightAccessReview = true
+	c.accessReviews = map[v1alpha1.AccessCheck]*v1alpha1.AccessCheckResult{}
+
+	checks := &[]v1alpha1.AccessCheck{}
+	c.dc.(*fakedynamic.FakeDynamicClient).PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		u := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		review := &authorizationv1.SelfSubjectAccessReview{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, review); err != nil {
+			t.Fatal(err)
+		}
+		attrs := review.Spec.ResourceAttributes
+		*checks = append(*checks, v1alpha1.AccessCheck{
+			Verb:      attrs.Verb,
+			Group:     attrs.Group,
+			Resource:  attrs.Resource,
+			Namespace: attrs.Namespace,
+			Name:      attrs.Name,
+		})
+		review.Status.Allowed = true
+		for _, verb := range denied {
+			if attrs.Verb == verb {
+				review.Status.Allowed = false
+				review.Status.Reason = "no RBAC policy matched"
+			}
+		}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(review)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return true, &unstructured.Unstructured{Object: obj}, nil
+	})
+	c.dc.(*fakedynamic.FakeDynamicClient).PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		u := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		review := &authorizationv1.SelfSubjectRulesReview{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, review); err != nil {
+			t.Fatal(err)
+		}
+		review.Status = authorizationv1.SubjectRulesReviewStatus{
+			ResourceRules: resourceRules,
+			Incomplete:    review.Spec.Namespace == "kube-system",
+		}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(review)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return true, &unstructured.Unstructured{Object: obj}, nil
+	})
+	return c, checks
+}
+
+var resourceRules = []authorizationv1.ResourceRule{
+	{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"configmaps"}},
+}
+
+func TestPreflightAccess(t *testing.T) {
+	cases := map[string]struct {
+		disabled bool
+		obj      *unstructured.Unstructured
+		verbs    []string
+		denied   []string
+		checks   []v1alpha1.AccessCheck
+		err      string
+	}{
+		"Disabled": {
+			disabled: true,
+			obj:      newConfigMap("web"),
+			verbs:    []string{"create", "get"},
+			checks:   []v1alpha1.AccessCheck{},
+		},
+		"Allowed": {
+			obj:   newConfigMap("web"),
+			verbs: []string{"create", "get"},
+			checks: []v1alpha1.AccessCheck{
+				// the name is not known to the authorizer for create
+				{Verb: "create", Resource: "configmaps", Namespace: "default"},
+				{Verb: "get", Resource: "configmaps", Namespace: "default", Name: "web"},
+			},
+		},
+		"ClusterScoped": {
+			obj: func() *unstructured.Unstructured {
+				u := &unstructured.Unstructured{}
+				u.SetGroupVersionKind(namespaceGVK)
+				u.SetName("team-a")
+				return u
+			}(),
+			verbs: []string{"delete"},
+			checks: []v1alpha1.AccessCheck{
+				{Verb: "delete", Resource: "namespaces", Name: "team-a"},
+			},
+		},
+		"Denied": {
+			obj:    newConfigMap("web"),
+			verbs:  []string{"update", "get"},
+			denied: []string{"update"},
+			checks: []v1alpha1.AccessCheck{
+				{Verb: "update", Resource: "configmaps", Namespace: "default", Name: "web"},
+			},
+			err: `not allowed to update configmaps default/web, reason: "no RBAC policy matched"`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, checks := newAccessReviewClient(t, tc.denied...)
+			c.preflightAccessReview = !tc.disabled
+
+			err := c.preflightAccess(context.Background(), tc.obj, tc.verbs...)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.checks, *checks)
+		})
+	}
+}
+
+func TestPreflightAccessCached(t *testing.T) {
+	c, checks := newAccessReviewClient(t)
+
+	assert.NoError(t, c.preflightAccess(context.Background(), newConfigMap("web"), "get"))
+	assert.NoError(t, c.preflightAccess(context.Background(), newConfigMap("web"), "get", "update"))
+	assert.Equal(t, []v1alpha1.AccessCheck{
+		{Verb: "get", Resource: "configmaps", Namespace: "default", Name: "web"},
+		{Verb: "update", Resource: "configmaps", Namespace: "default", Name: "web"},
+	}, *checks)
+}
+
+func TestCreatePreflightAccess(t *testing.T) {
+	cases := map[string]struct {
+		adoptExisting bool
+		checks        []v1alpha1.AccessCheck
+	}{
+		"Create": {
+			checks: []v1alpha1.AccessCheck{
+				{Verb: "create", Resource: "configmaps", Namespace: "default"},
+				{Verb: "get", Resource: "configmaps", Namespace: "default", Name: "web"},
+			},
+		},
+		// an existing object is adopted with an update
+		"AdoptExisting": {
+			adoptExisting: true,
+			checks: []v1alpha1.AccessCheck{
+				{Verb: "create", Resource: "configmaps", Namespace: "default"},
+				{Verb: "get", Resource: "configmaps", Namespace: "default", Name: "web"},
+				{Verb: "update", Resource: "configmaps", Namespace: "default", Name: "web"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, checks := newAccessReviewClient(t)
+			c.defaults = &objectDefaults{}
+			c.adoptExisting = tc.adoptExisting
+
+			b, err := json.Marshal(newConfigMap("web"))
+			assert.NoError(t, err)
+			_, diags := resourceKubernetesManifestCreate(context.Background(), &sdkschema.ResourceObject{Obj: b, DryRun: true}, c)
+			assert.False(t, diags.HasError())
+			assert.Equal(t, tc.checks, *checks)
+		})
+	}
+}
+
+func TestReviewRules(t *testing.T) {
+	c, _ := newAccessReviewClient(t)
+
+	rules, err := c.reviewRules(context.Background(), "default")
+	assert.NoError(t, err)
+	assert.Equal(t, &v1alpha1.AccessRules{Namespace: "default", ResourceRules: resourceRules}, rules)
+
+	rules, err = c.reviewRules(context.Background(), "kube-system")
+	assert.NoError(t, err)
+	assert.Equal(t, &v1alpha1.AccessRules{Namespace: "kube-system", ResourceRules: resourceRules, Incomplete: true}, rules)
+}
+
+func TestDataSourceKubernetesAccessReviewRead(t *testing.T) {
+	checks := []v1alpha1.AccessCheck{
+		{Verb: "get", Resource: "configmaps", Namespace: "default"},
+		{Verb: "delete", Resource: "configmaps", Namespace: "default"},
+	}
+	cases := map[string]struct {
+		failOnDenied bool
+		denied       []string
+		status       v1alpha1.AccessReviewStatus
+		err          string
+	}{
+		"Allowed": {
+			status: v1alpha1.AccessReviewStatus{
+				Allowed: true,
+				Checks: []v1alpha1.AccessCheckResult{
+					{AccessCheck: checks[0], Allowed: true},
+					{AccessCheck: checks[1], Allowed: true},
+				},
+				Rules: []v1alpha1.AccessRules{{Namespace: "default", ResourceRules: resourceRules}},
+			},
+		},
+		"Denied": {
+			denied: []string{"delete"},
+			status: v1alpha1.AccessReviewStatus{
+				Allowed: false,
+				Checks: []v1alpha1.AccessCheckResult{
+					{AccessCheck: checks[0], Allowed: true},
+					{AccessCheck: checks[1], Reason: "no RBAC policy matched"},
+				},
+				Rules: []v1alpha1.AccessRules{{Namespace: "default", ResourceRules: resourceRules}},
+			},
+		},
+		"FailOnDenied": {
+			failOnDenied: true,
+			denied:       []string{"delete"},
+			err:          `access denied, namespace: "default", name: "", reason: "no RBAC policy matched"`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, _ := newAccessReviewClient(t, tc.denied...)
+
+			b, err := json.Marshal(&v1alpha1.AccessReview{Spec: v1alpha1.AccessReviewSpec{
+				Checks:          checks,
+				RulesNamespaces: []string{"default"},
+				FailOnDenied:    &tc.failOnDenied,
+			}})
+			assert.NoError(t, err)
+			b, diags := dataSourceKubernetesAccessReviewRead(context.Background(), &sdkschema.ResourceObject{Obj: b}, c)
+			if tc.err != "" {
+				assert.True(t, diags.HasError())
+				assert.Equal(t, tc.err, diags[0].GetDetail())
+				return
+			}
+			assert.False(t, diags.HasError())
+			review := &v1alpha1.AccessReview{}
+			assert.NoError(t, json.Unmarshal(b, review))
+			assert.Equal(t, tc.status, review.Status)
+		})
+	}
+}
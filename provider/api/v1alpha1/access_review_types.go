@@ -0,0 +1,81 @@
+package v1alpha1
+
+import (
+	"reflect"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type AccessReviewSpec struct {
+	// Checks defines the list of access checks to be validated against the
+	// identity of the kubeconfig using SelfSubjectAccessReviews.
+	Checks []AccessCheck `json:"checks,omitempty" yaml:"checks,omitempty"`
+	// RulesNamespaces defines the namespaces for which the rules of the identity
+	// are retrieved using SelfSubjectRulesReviews.
+	RulesNamespaces []string `json:"rulesNamespaces,omitempty" yaml:"rulesNamespaces,omitempty"`
+	// FailOnDenied returns an error when one of the checks is not allowed
+	// +kubebuilder:default=false
+	FailOnDenied *bool `json:"failOnDenied,omitempty" yaml:"failOnDenied,omitempty"`
+}
+
+type AccessCheck struct {
+	// Verb is a kubernetes resource API verb, like: get, list, watch, create, update, delete, proxy.
+	Verb string `json:"verb" yaml:"verb"`
+	// Group is the API Group of the Resource.
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+	// Resource is one of the existing resource types.
+	Resource string `json:"resource" yaml:"resource"`
+	// Subresource is one of the existing subresource types.
+	Subresource string `json:"subresource,omitempty" yaml:"subresource,omitempty"`
+	// Namespace is the namespace of the action being requested.
+	// "" means all namespaces for namespaced resources or a cluster-scoped resource.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	// Name is the name of the resource being requested for a get or delete.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+type AccessReviewStatus struct {
+	// Allowed is true when all checks are allowed
+	Allowed bool `json:"allowed" yaml:"allowed"`
+	// Checks provide the result of the individual access checks
+	Checks []AccessCheckResult `json:"checks,omitempty" yaml:"checks,omitempty"`
+	// Rules provide the rules of the identity per namespace
+	Rules []AccessRules `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+type AccessCheckResult struct {
+	AccessCheck `json:",inline" yaml:",inline"`
+	// Allowed indicates the action is allowed
+	Allowed bool `json:"allowed" yaml:"allowed"`
+	// Denied indicates the action is explicitly denied
+	Denied bool `json:"denied,omitempty" yaml:"denied,omitempty"`
+	// Reason is the reason provided by the authorizer
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+type AccessRules struct {
+	// Namespace for which the rules are retrieved
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// ResourceRules is the list of actions the identity is allowed to perform on resources.
+	ResourceRules []authorizationv1.ResourceRule `json:"resourceRules,omitempty" yaml:"resourceRules,omitempty"`
+	// NonResourceRules is the list of actions the identity is allowed to perform on non-resources.
+	NonResourceRules []authorizationv1.NonResourceRule `json:"nonResourceRules,omitempty" yaml:"nonResourceRules,omitempty"`
+	// Incomplete is true when the rules returned by the server are incomplete
+	Incomplete bool `json:"incomplete,omitempty" yaml:"incomplete,omitempty"`
+	// EvaluationError reports errors during the rule evaluation
+	EvaluationError string `json:"evaluationError,omitempty" yaml:"evaluationError,omitempty"`
+}
+
+// AccessReview is the input and output of the kubernetes_access_review data source
+type AccessReview struct {
+	metav1.TypeMeta   `json:",inline" yaml:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	Spec   AccessReviewSpec   `json:"spec,omitempty" yaml:"spec,omitempty"`
+	Status AccessReviewStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+var (
+	AccessReviewKind = reflect.TypeOf(AccessReview{}).Name()
+)
@@ -0,0 +1,15 @@
+package v1alpha1
+
+const (
+	// SensitiveAnnotation marks an object returned by the provider as sensitive,
+	// its content should not be displayed or logged. It is advisory: the object
+	// is returned as is and it is up to the consumer to honor the annotation.
+	SensitiveAnnotation = Group + "/sensitive"
+	// DecodeAnnotation set to true on the input of the kubernetes_secret data source
+	// returns the decoded values of the secret in stringData. The decoded values are
+	// recorded in plaintext in the state, hence the values are not decoded by default.
+	DecodeAnnotation = Group + "/decode"
+	// FieldValidationAnnotation overrides the field validation of the provider
+	// for the object: Ignore, Warn or Strict.
+	FieldValidationAnnotation = Group + "/field-validation"
+)
@@ -60,6 +60,11 @@ type ProviderConfigSpec struct {
 	// +kubebuilder:validation:MaxLength=64
 	ConfigContext *string `json:"configContext,omitempty" yaml:"configContext,omitempty"`
 
+	// Namespace is the namespace of the namespaced manifests without namespace.
+	// When not set the namespace of the kube config context is used.
+	// +kubebuilder:validation:MaxLength=63
+	Namespace *string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MaxLength=64
 	ConfigContextAuthInfo *string `json:"configContextAuthInfo,omitempty" yaml:"configContextAuthInfo,omitempty"`
@@ -83,6 +88,106 @@ type ProviderConfigSpec struct {
 
 	// Exec executes a command to get the authentication context
 	//Exec *ExecContext `json:"exec,omitempty" yaml:"exec,omitempty"`
+
+	// AcceptPendingLoadBalancers reports LoadBalancer services as ready when no
+	// load balancer ingress is assigned, e.g. on clusters without a cloud controller.
+	// +kubebuilder:default=false
+	AcceptPendingLoadBalancers *bool `json:"acceptPendingLoadBalancers,omitempty" yaml:"acceptPendingLoadBalancers,omitempty"`
+
+	// PreflightAccessReview validates with SelfSubjectAccessReviews that the identity
+	// is allowed to perform the verbs required by a kubernetes_manifest operation
+	// during the dry-run, before anything gets mutated.
+	// +kubebuilder:default=false
+	PreflightAccessReview *bool `json:"preflightAccessReview,omitempty" yaml:"preflightAccessReview,omitempty"`
+
+	// StatusRules define CEL expressions computing the status of custom resources.
+	// They take precedence over the built-in status rules.
+	StatusRules []StatusRule `json:"statusRules,omitempty" yaml:"statusRules,omitempty"`
+
+	// ProgressDeadlines report resources as failed when their status made no
+	// progress within the timeout of their GroupKind. On create and update the
+	// status of these resources is polled until the resource timeout of 5m, so a
+	// longer timeout never reports a resource as failed.
+	ProgressDeadlines []ProgressDeadline `json:"progressDeadlines,omitempty" yaml:"progressDeadlines,omitempty"`
+
+	// FieldValidation instructs the API server how to handle unknown and duplicate
+	// fields of the manifests on create and update: Ignore, Warn or Strict. When not
+	// set the API server default applies. It is overridden per resource with the
+	// kubernetes.provider.kform.dev/field-validation annotation.
+	// +kubebuilder:validation:Enum=Ignore;Warn;Strict
+	FieldValidation *string `json:"fieldValidation,omitempty" yaml:"fieldValidation,omitempty"`
+
+	// AutoConvertDeprecatedAPIs replaces the apiVersion of manifests using an apiVersion
+	// removed from the cluster by its replacement, when the manifest is valid in the
+	// replacement apiVersion without any other change. The state keeps the configured
+	// apiVersion, such that it does not differ from the manifest on every plan.
+	// +kubebuilder:default=false
+	AutoConvertDeprecatedAPIs *bool `json:"autoConvertDeprecatedAPIs,omitempty" yaml:"autoConvertDeprecatedAPIs,omitempty"`
+
+	// DefaultLabels are merged into the labels of every object on create and update,
+	// the labels of the manifest take precedence.
+	DefaultLabels map[string]string `json:"defaultLabels,omitempty" yaml:"defaultLabels,omitempty"`
+
+	// DefaultAnnotations are merged into the annotations of every object on create and
+	// update, the annotations of the manifest take precedence.
+	DefaultAnnotations map[string]string `json:"defaultAnnotations,omitempty" yaml:"defaultAnnotations,omitempty"`
+
+	// PropagateDefaultsToPodTemplates merges the default labels and annotations also
+	// into the pod templates of the workload kinds, e.g. Deployment and CronJob.
+	// +kubebuilder:default=false
+	PropagateDefaultsToPodTemplates *bool `json:"propagateDefaultsToPodTemplates,omitempty" yaml:"propagateDefaultsToPodTemplates,omitempty"`
+
+	// Apply configures how update applies the manifests to the objects in the
+	// cluster, and whether create adopts objects that already exist.
+	Apply *ApplyConfig `json:"apply,omitempty" yaml:"apply,omitempty"`
+}
+
+// ApplyConfig configures how an existing object is updated to its manifest.
+type ApplyConfig struct {
+	// AdoptExisting updates an object that already exists on create, instead of
+	// failing with AlreadyExists. The object is taken over by kform.
+	// +kubebuilder:default=false
+	AdoptExisting *bool `json:"adoptExisting,omitempty" yaml:"adoptExisting,omitempty"`
+	// RequireManagedByKform refuses to update existing objects that are not labeled
+	// app.kubernetes.io/managed-by=kform, e.g. objects created by another tool. The
+	// label is added to the default labels, such that the objects created by kform
+	// are labeled.
+	// +kubebuilder:default=false
+	RequireManagedByKform *bool `json:"requireManagedByKform,omitempty" yaml:"requireManagedByKform,omitempty"`
+	// RetainLabels retains the labels of the existing object that are not set in
+	// the manifest, e.g. labels added by other controllers.
+	// +kubebuilder:default=false
+	RetainLabels *bool `json:"retainLabels,omitempty" yaml:"retainLabels,omitempty"`
+	// PreserveFields are the fields of the existing object in dot notation, e.g.
+	// spec.replicas, that are preserved on update, e.g. fields owned by an autoscaler.
+	PreserveFields []string `json:"preserveFields,omitempty" yaml:"preserveFields,omitempty"`
+}
+
+// ProgressDeadline defines the time the resources of a GroupKind can be in progress
+// without any change in their status before they are reported as failed
+type ProgressDeadline struct {
+	// Group of the resource, empty for the core group
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+	// Kind of the resource
+	Kind string `json:"kind" yaml:"kind"`
+	// Timeout after which a resource without progress is reported as failed, e.g. 10m
+	Timeout metav1.Duration `json:"timeout" yaml:"timeout"`
+}
+
+// StatusRule defines the CEL expressions computing the status of the resources of a GroupKind
+type StatusRule struct {
+	// Group of the resource, empty for the core group
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+	// Kind of the resource
+	Kind string `json:"kind" yaml:"kind"`
+	// Ready is a CEL expression returning a bool that indicates the resource is ready.
+	Ready string `json:"ready" yaml:"ready"`
+	// Failed is an optional CEL expression returning a bool that indicates the
+	// resource failed. It is evaluated before the Ready expression.
+	Failed string `json:"failed,omitempty" yaml:"failed,omitempty"`
+	// Message is an optional CEL expression returning a string that describes the
+	// status of the resource.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
 }
 
 type ExecContext struct {
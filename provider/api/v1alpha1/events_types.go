@@ -0,0 +1,54 @@
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type EventsSpec struct {
+	// InvolvedObject references the object for which the events are listed
+	InvolvedObject ObjectReference `json:"involvedObject" yaml:"involvedObject"`
+	// Types filters the events on their type, e.g. Warning or Normal.
+	// When not supplied all event types are returned.
+	Types []string `json:"types,omitempty" yaml:"types,omitempty"`
+	// Limit the amount of events returned, the most recent events are returned first.
+	Limit *int `json:"limit,omitempty" yaml:"limit,omitempty"`
+}
+
+type EventsStatus struct {
+	// Events of the involved object sorted from most to least recent
+	Events []Event `json:"events,omitempty" yaml:"events,omitempty"`
+}
+
+type Event struct {
+	// InvolvedObject references the object this event is about
+	InvolvedObject ObjectReference `json:"involvedObject" yaml:"involvedObject"`
+	// Type of the event, e.g. Normal or Warning
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// Reason of the event, e.g. FailedScheduling
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	// Message is a human readable description of the event
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// Count is the number of times the event occurred
+	Count int32 `json:"count,omitempty" yaml:"count,omitempty"`
+	// Source is the component reporting the event
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+	// FirstTimestamp is the time the event was first recorded
+	FirstTimestamp metav1.Time `json:"firstTimestamp,omitempty" yaml:"firstTimestamp,omitempty"`
+	// LastTimestamp is the time the event was last recorded
+	LastTimestamp metav1.Time `json:"lastTimestamp,omitempty" yaml:"lastTimestamp,omitempty"`
+}
+
+// Events is the input and output of the kubernetes_events data source
+type Events struct {
+	metav1.TypeMeta   `json:",inline" yaml:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	Spec   EventsSpec   `json:"spec,omitempty" yaml:"spec,omitempty"`
+	Status EventsStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+var (
+	EventsKind = reflect.TypeOf(Events{}).Name()
+)
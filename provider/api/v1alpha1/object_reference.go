@@ -0,0 +1,28 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ObjectReference references a kubernetes object by apiVersion, kind, namespace and name
+type ObjectReference struct {
+	// APIVersion of the referenced object
+	APIVersion string `json:"apiVersion" yaml:"apiVersion"`
+	// Kind of the referenced object
+	Kind string `json:"kind" yaml:"kind"`
+	// Namespace of the referenced object, empty for cluster-scoped objects
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	// Name of the referenced object
+	Name string `json:"name" yaml:"name"`
+}
+
+// Unstructured returns an unstructured object with the apiVersion, kind, namespace and
+// name of the reference set
+func (r ObjectReference) Unstructured() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(r.APIVersion)
+	u.SetKind(r.Kind)
+	u.SetNamespace(r.Namespace)
+	u.SetName(r.Name)
+	return u
+}
@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type OwnerGraphSpec struct {
+	// Root references the object for which the dependents are resolved
+	Root ObjectReference `json:"root" yaml:"root"`
+	// Kinds restricts the kinds of the dependents being resolved. When not supplied all
+	// namespaced kinds that can be listed are discovered.
+	Kinds []metav1.GroupKind `json:"kinds,omitempty" yaml:"kinds,omitempty"`
+	// MaxDepth limits the depth of the dependency tree
+	// +kubebuilder:default=10
+	MaxDepth *int `json:"maxDepth,omitempty" yaml:"maxDepth,omitempty"`
+}
+
+type OwnerGraphStatus struct {
+	// Root is the root of the dependency tree
+	Root *OwnerGraphNode `json:"root,omitempty" yaml:"root,omitempty"`
+}
+
+type OwnerGraphNode struct {
+	ObjectReference `json:",inline" yaml:",inline"`
+	// UID of the object
+	UID types.UID `json:"uid" yaml:"uid"`
+	// Result provides the computed status of the object
+	Result *StatusResult `json:"result,omitempty" yaml:"result,omitempty"`
+	// Dependents are the objects owned by this object
+	Dependents []*OwnerGraphNode `json:"dependents,omitempty" yaml:"dependents,omitempty"`
+}
+
+// StatusResult is the computed status of an object
+type StatusResult struct {
+	// Status is True when the object is ready
+	Status metav1.ConditionStatus `json:"status" yaml:"status"`
+	// Reason of the status, e.g. Ready, InProgress, Failed or NotFound
+	Reason string `json:"reason" yaml:"reason"`
+	// Message describing the status
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// Checks are the checks evaluated to compute the status, in the order of evaluation
+	Checks []StatusCheck `json:"checks,omitempty" yaml:"checks,omitempty"`
+}
+
+// StatusCheck is a check evaluated to compute the status of an object
+type StatusCheck struct {
+	// Name of the check, e.g. the status field or condition type
+	Name string `json:"name" yaml:"name"`
+	// Passed indicates the observed value meets the expected value
+	Passed bool `json:"passed" yaml:"passed"`
+	// Observed value
+	Observed string `json:"observed,omitempty" yaml:"observed,omitempty"`
+	// Expected value
+	Expected string `json:"expected,omitempty" yaml:"expected,omitempty"`
+	// Message of the condition or the expression of a rule
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// LastTransitionTime of the condition
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty" yaml:"lastTransitionTime,omitempty"`
+}
+
+// OwnerGraph is the input and output of the kubernetes_owner_graph data source
+type OwnerGraph struct {
+	metav1.TypeMeta   `json:",inline" yaml:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	Spec   OwnerGraphSpec   `json:"spec,omitempty" yaml:"spec,omitempty"`
+	Status OwnerGraphStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+var (
+	OwnerGraphKind = reflect.TypeOf(OwnerGraph{}).Name()
+)
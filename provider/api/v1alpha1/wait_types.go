@@ -0,0 +1,47 @@
+package v1alpha1
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type WaitSpec struct {
+	// Object references the object to wait for
+	Object ObjectReference `json:"object" yaml:"object"`
+	// Condition waits until the object has a status condition of the given type and status.
+	Condition *WaitCondition `json:"condition,omitempty" yaml:"condition,omitempty"`
+	// JSONPath waits until the JSONPath expression evaluated against the object returns
+	// the given value.
+	JSONPath *WaitJSONPath `json:"jsonPath,omitempty" yaml:"jsonPath,omitempty"`
+	// When neither a condition or a jsonPath is supplied, the data source waits until the
+	// computed status of the object is ready.
+}
+
+type WaitCondition struct {
+	// Type of the condition, e.g. Ready
+	Type string `json:"type" yaml:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	// +kubebuilder:default=True
+	Status metav1.ConditionStatus `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+type WaitJSONPath struct {
+	// Path defines the JSONPath expression, e.g. {.status.phase}
+	Path string `json:"path" yaml:"path"`
+	// Value the JSONPath expression should return. When not supplied, the wait
+	// succeeds when the expression returns a non empty result.
+	Value *string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// Wait is the input of the kubernetes_wait data source
+type Wait struct {
+	metav1.TypeMeta   `json:",inline" yaml:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	Spec WaitSpec `json:"spec,omitempty" yaml:"spec,omitempty"`
+}
+
+var (
+	WaitKind = reflect.TypeOf(Wait{}).Name()
+)
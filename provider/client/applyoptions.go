@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// LabelManagedBy is the label identifying the tool managing the object
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+	// ManagedByKform is the value of the LabelManagedBy label of objects managed by kform
+	ManagedByKform = "kform"
+)
+
+// MustBeManagedByKform returns an ApplyOption that refuses to update an existing
+// object that is not labeled as managed by kform.
+func MustBeManagedByKform() ApplyOption {
+	return func(_ context.Context, current, _ runtime.Object) error {
+		m, err := meta.Accessor(current)
+		if err != nil {
+			return err
+		}
+		if m.GetLabels()[LabelManagedBy] != ManagedByKform {
+			return fmt.Errorf("existing object %s is not managed by kform, expected label %s=%s",
+				m.GetName(), LabelManagedBy, ManagedByKform)
+		}
+		return nil
+	}
+}
+
+// PreserveFields returns an ApplyOption that copies the fields, in dot notation
+// e.g. spec.replicas, of the current object to the desired object, such that
+// fields owned by other controllers are not overwritten. Fields that are not
+// set on the current object are left untouched.
+func PreserveFields(paths ...string) ApplyOption {
+	return func(_ context.Context, current, desired runtime.Object) error {
+		cu, ok := current.(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("unsupported object type %T, expected *unstructured.Unstructured", current)
+		}
+		du, ok := desired.(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("unsupported object type %T, expected *unstructured.Unstructured", desired)
+		}
+		for _, path := range paths {
+			fields := strings.Split(path, ".")
+			v, found, err := unstructured.NestedFieldCopy(cu.Object, fields...)
+			if err != nil {
+				return fmt.Errorf("cannot preserve field %s: %w", path, err)
+			}
+			if !found {
+				continue
+			}
+			if err := unstructured.SetNestedField(du.Object, v, fields...); err != nil {
+				return fmt.Errorf("cannot preserve field %s: %w", path, err)
+			}
+		}
+		return nil
+	}
+}
+
+// RetainLabels returns an ApplyOption that retains the labels of the current
+// object that are not set on the desired object, e.g. labels added by other
+// controllers. The labels of the desired object take precedence.
+func RetainLabels() ApplyOption {
+	return func(_ context.Context, current, desired runtime.Object) error {
+		cm, err := meta.Accessor(current)
+		if err != nil {
+			return err
+		}
+		dm, err := meta.Accessor(desired)
+		if err != nil {
+			return err
+		}
+		labels := map[string]string{}
+		for k, v := range cm.GetLabels() {
+			labels[k] = v
+		}
+		for k, v := range dm.GetLabels() {
+			labels[k] = v
+		}
+		if len(labels) > 0 {
+			dm.SetLabels(labels)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newDeployment(replicas int64, labels map[string]string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "web",
+			"namespace": "default",
+		},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+		},
+	}}
+	u.SetLabels(labels)
+	return u
+}
+
+func TestApplyOptions(t *testing.T) {
+	cases := map[string]struct {
+		opt      ApplyOption
+		current  *unstructured.Unstructured
+		desired  *unstructured.Unstructured
+		expected *unstructured.Unstructured
+		err      bool
+	}{
+		"ManagedByKform": {
+			opt:      MustBeManagedByKform(),
+			current:  newDeployment(1, map[string]string{LabelManagedBy: ManagedByKform}),
+			desired:  newDeployment(2, nil),
+			expected: newDeployment(2, nil),
+		},
+		"NotManagedByKform": {
+			opt:     MustBeManagedByKform(),
+			current: newDeployment(1, map[string]string{LabelManagedBy: "helm"}),
+			desired: newDeployment(2, nil),
+			err:     true,
+		},
+		"PreserveFields": {
+			opt:      PreserveFields("spec.replicas", "spec.paused"),
+			current:  newDeployment(5, nil),
+			desired:  newDeployment(2, nil),
+			expected: newDeployment(5, nil),
+		},
+		"RetainLabels": {
+			opt:      RetainLabels(),
+			current:  newDeployment(1, map[string]string{"a": "current", "b": "current"}),
+			desired:  newDeployment(1, map[string]string{"b": "desired"}),
+			expected: newDeployment(1, map[string]string{"a": "current", "b": "desired"}),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.opt(context.Background(), tc.current, tc.desired)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, tc.desired)
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// New returns a Client for unstructured objects backed by the dynamic client,
+// the mapper maps the GroupVersionKind of the objects to their resource.
+func New(dc dynamic.Interface, mapper meta.RESTMapper) Client {
+	return &dynamicClient{
+		dc:     dc,
+		mapper: mapper,
+	}
+}
+
+type dynamicClient struct {
+	dc     dynamic.Interface
+	mapper meta.RESTMapper
+}
+
+// resource returns the resource interface of the GroupVersionKind in the namespace.
+func (r *dynamicClient) resource(gvk schema.GroupVersionKind, namespace string, requireNamespace bool) (dynamic.ResourceInterface, error) {
+	m, err := r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	if m.Scope.Name() != meta.RESTScopeNameNamespace {
+		return r.dc.Resource(m.Resource), nil
+	}
+	if namespace == "" && requireNamespace {
+		return nil, fmt.Errorf("expected namespace, got %s", namespace)
+	}
+	return r.dc.Resource(m.Resource).Namespace(namespace), nil
+}
+
+func asUnstructured(obj client.Object) (*unstructured.Unstructured, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unsupported object type %T, expected *unstructured.Unstructured", obj)
+	}
+	return u, nil
+}
+
+func (r *dynamicClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	u, err := asUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	ri, err := r.resource(u.GroupVersionKind(), key.Namespace, true)
+	if err != nil {
+		return err
+	}
+	o := &client.GetOptions{}
+	o.ApplyOptions(opts)
+	newObj, err := ri.Get(ctx, key.Name, *o.AsGetOptions())
+	if err != nil {
+		return err
+	}
+	*u = *newObj
+	return nil
+}
+
+func (r *dynamicClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	ul, ok := list.(*unstructured.UnstructuredList)
+	if !ok {
+		return fmt.Errorf("unsupported list type %T, expected *unstructured.UnstructuredList", list)
+	}
+	gvk := ul.GroupVersionKind()
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+
+	o := &client.ListOptions{}
+	o.ApplyOptions(opts)
+	ri, err := r.resource(gvk, o.Namespace, false)
+	if err != nil {
+		return err
+	}
+	newList, err := ri.List(ctx, *o.AsListOptions())
+	if err != nil {
+		return err
+	}
+	*ul = *newList
+	return nil
+}
+
+func (r *dynamicClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	u, err := asUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	ri, err := r.resource(u.GroupVersionKind(), u.GetNamespace(), true)
+	if err != nil {
+		return err
+	}
+	o := &client.CreateOptions{}
+	o.ApplyOptions(opts)
+	newObj, err := ri.Create(ctx, u, *o.AsCreateOptions())
+	if err != nil {
+		return err
+	}
+	*u = *newObj
+	return nil
+}
+
+func (r *dynamicClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	u, err := asUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	ri, err := r.resource(u.GroupVersionKind(), u.GetNamespace(), true)
+	if err != nil {
+		return err
+	}
+	o := &client.UpdateOptions{}
+	o.ApplyOptions(opts)
+	newObj, err := ri.Update(ctx, u, *o.AsUpdateOptions())
+	if err != nil {
+		return err
+	}
+	*u = *newObj
+	return nil
+}
+
+func (r *dynamicClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	u, err := asUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	ri, err := r.resource(u.GroupVersionKind(), u.GetNamespace(), true)
+	if err != nil {
+		return err
+	}
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+	o := &client.PatchOptions{}
+	o.ApplyOptions(opts)
+	newObj, err := ri.Patch(ctx, u.GetName(), patch.Type(), data, *o.AsPatchOptions())
+	if err != nil {
+		return err
+	}
+	*u = *newObj
+	return nil
+}
+
+func (r *dynamicClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	u, err := asUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	ri, err := r.resource(u.GroupVersionKind(), u.GetNamespace(), true)
+	if err != nil {
+		return err
+	}
+	o := &client.DeleteOptions{}
+	o.ApplyOptions(opts)
+	return ri.Delete(ctx, u.GetName(), *o.AsDeleteOptions())
+}
+
+func (r *dynamicClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	u, err := asUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	o := &client.DeleteAllOfOptions{}
+	o.ApplyOptions(opts)
+	ri, err := r.resource(u.GroupVersionKind(), o.Namespace, true)
+	if err != nil {
+		return err
+	}
+	return ri.DeleteCollection(ctx, *o.AsDeleteOptions(), *o.AsListOptions())
+}
+
+// Apply creates the object when it does not exist, otherwise the ApplyOptions
+// are called with the current and desired object before the current object is
+// updated to the desired object.
+func (r *dynamicClient) Apply(ctx context.Context, obj client.Object, opts ...ApplyOption) error {
+	return apply(ctx, r, obj, opts...)
+}
+
+type readerWriter interface {
+	client.Reader
+	client.Writer
+}
+
+// apply implements Apply with the reader and writer of the client. The update
+// is made with the resourceVersion of the desired object, such that it fails
+// with a Conflict when the object changed since the caller observed it. The
+// resourceVersion of the current object is only used when the desired object
+// has none.
+func apply(ctx context.Context, c readerWriter, obj client.Object, opts ...ApplyOption) error {
+	desired, err := asUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(desired.GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKeyFromObject(desired), current); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		// the object observed by the caller was deleted
+		desired.SetResourceVersion("")
+		return c.Create(ctx, desired)
+	}
+	for _, fn := range opts {
+		if err := fn(ctx, current, desired); err != nil {
+			return err
+		}
+	}
+	if desired.GetResourceVersion() == "" {
+		desired.SetResourceVersion(current.GetResourceVersion())
+	}
+	return c.Update(ctx, desired)
+}
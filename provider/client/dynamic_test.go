@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var deploymentGVK = schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+// newFakeClient returns a Client backed by a fake dynamic client with the objects,
+// like the API server an update with a changed resourceVersion is a Conflict.
+func newFakeClient(objs ...runtime.Object) Client {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(deploymentGVK, meta.RESTScopeNamespace)
+	dc := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		deploymentGR.WithVersion("v1"): "DeploymentList",
+	}, objs...)
+	dc.PrependReactor("update", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		update := action.(k8stesting.UpdateAction)
+		obj, err := meta.Accessor(update.GetObject())
+		if err != nil {
+			return true, nil, err
+		}
+		current, err := dc.Tracker().Get(update.GetResource(), update.GetNamespace(), obj.GetName())
+		if err != nil {
+			return false, nil, nil
+		}
+		currentObj, err := meta.Accessor(current)
+		if err != nil {
+			return true, nil, err
+		}
+		if obj.GetResourceVersion() != currentObj.GetResourceVersion() {
+			return true, nil, apierrors.NewConflict(update.GetResource().GroupResource(), obj.GetName(), errors.New("the object has been modified"))
+		}
+		return false, nil, nil
+	})
+	return New(dc, mapper)
+}
+
+// recordingOption records the writes it is passed to.
+type recordingOption struct {
+	writes []string
+}
+
+func (r *recordingOption) ApplyToCreate(*client.CreateOptions) { r.writes = append(r.writes, "create") }
+func (r *recordingOption) ApplyToUpdate(*client.UpdateOptions) { r.writes = append(r.writes, "update") }
+
+func TestApply(t *testing.T) {
+	cases := map[string]struct {
+		current  *unstructured.Unstructured
+		desired  *unstructured.Unstructured
+		opts     []ApplyOption
+		expected *unstructured.Unstructured
+		writes   []string
+		err      bool
+	}{
+		"CreateWhenNotFound": {
+			desired:  newDeployment(3, map[string]string{"app": "web"}),
+			opts:     []ApplyOption{RetainLabels()},
+			expected: newDeployment(3, map[string]string{"app": "web"}),
+			writes:   []string{"create"},
+		},
+		"UpdateWithApplyOptions": {
+			current: func() *unstructured.Unstructured {
+				u := newDeployment(5, map[string]string{"team": "a"})
+				u.SetResourceVersion("7")
+				return u
+			}(),
+			desired:  newDeployment(3, map[string]string{"app": "web"}),
+			opts:     []ApplyOption{RetainLabels(), PreserveFields("spec.replicas")},
+			expected: newDeployment(5, map[string]string{"app": "web", "team": "a"}),
+			writes:   []string{"update"},
+		},
+		// the resourceVersion observed by the caller is sent with the update
+		"StaleResourceVersion": {
+			current: func() *unstructured.Unstructured {
+				u := newDeployment(5, nil)
+				u.SetResourceVersion("7")
+				return u
+			}(),
+			desired: func() *unstructured.Unstructured {
+				u := newDeployment(3, nil)
+				u.SetResourceVersion("6")
+				return u
+			}(),
+			expected: newDeployment(5, nil),
+			writes:   []string{"update"},
+			err:      true,
+		},
+		"RefuseUnmanaged": {
+			current:  newDeployment(5, nil),
+			desired:  newDeployment(3, map[string]string{LabelManagedBy: ManagedByKform}),
+			opts:     []ApplyOption{MustBeManagedByKform()},
+			expected: newDeployment(5, nil),
+			err:      true,
+		},
+		"UpdateManaged": {
+			current:  newDeployment(5, map[string]string{LabelManagedBy: ManagedByKform}),
+			desired:  newDeployment(3, map[string]string{LabelManagedBy: ManagedByKform}),
+			opts:     []ApplyOption{MustBeManagedByKform()},
+			expected: newDeployment(3, map[string]string{LabelManagedBy: ManagedByKform}),
+			writes:   []string{"update"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var objs []runtime.Object
+			if tc.current != nil {
+				objs = append(objs, tc.current)
+			}
+			rec := &recordingOption{}
+			c := WithWriteOptions(newFakeClient(objs...), rec, FieldValidation("Strict"))
+
+			err := c.Apply(context.Background(), tc.desired, tc.opts...)
+			if tc.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.writes, rec.writes)
+
+			got := &unstructured.Unstructured{}
+			got.SetGroupVersionKind(deploymentGVK)
+			assert.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "web"}, got))
+			replicas, _, _ := unstructured.NestedInt64(got.Object, "spec", "replicas")
+			expectedReplicas, _, _ := unstructured.NestedInt64(tc.expected.Object, "spec", "replicas")
+			assert.Equal(t, expectedReplicas, replicas)
+			assert.Equal(t, tc.expected.GetLabels(), got.GetLabels())
+		})
+	}
+}
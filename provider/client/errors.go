@@ -0,0 +1,163 @@
+package client
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrorClass classifies the errors returned by the API server.
+type ErrorClass string
+
+const (
+	ErrorClassNotFound             ErrorClass = "NotFound"
+	ErrorClassAlreadyExists        ErrorClass = "AlreadyExists"
+	ErrorClassConflict             ErrorClass = "Conflict"
+	ErrorClassInvalid              ErrorClass = "Invalid"
+	ErrorClassForbidden            ErrorClass = "Forbidden"
+	ErrorClassTooManyRequests      ErrorClass = "TooManyRequests"
+	ErrorClassServerTimeout        ErrorClass = "ServerTimeout"
+	ErrorClassServerError          ErrorClass = "ServerError"
+	ErrorClassWebhookUnavailable   ErrorClass = "WebhookUnavailable"
+	ErrorClassNamespaceTerminating ErrorClass = "NamespaceTerminating"
+	ErrorClassUnknown              ErrorClass = "Unknown"
+)
+
+// webhookUnavailableMessages are the messages of the API server when a webhook
+// cannot be reached, e.g. while its pods are being (re)started. A webhook that
+// timed out may have processed the request, it is not retried.
+var webhookUnavailableMessages = []string{
+	"connection refused",
+	"no endpoints available for service",
+}
+
+// Classify returns the class of the error, errors not returned by the API
+// server are classified as ErrorClassUnknown.
+func Classify(err error) ErrorClass {
+	var status apierrors.APIStatus
+	if err == nil || !errors.As(err, &status) {
+		return ErrorClassUnknown
+	}
+	switch {
+	case apierrors.IsNotFound(err):
+		return ErrorClassNotFound
+	case apierrors.IsAlreadyExists(err):
+		return ErrorClassAlreadyExists
+	case apierrors.IsConflict(err):
+		return ErrorClassConflict
+	case apierrors.HasStatusCause(err, corev1.NamespaceTerminatingCause):
+		// checked before Forbidden as the API server returns it as Forbidden
+		return ErrorClassNamespaceTerminating
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return ErrorClassInvalid
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return ErrorClassForbidden
+	case apierrors.IsTooManyRequests(err):
+		return ErrorClassTooManyRequests
+	case apierrors.IsServerTimeout(err), apierrors.IsTimeout(err):
+		return ErrorClassServerTimeout
+	}
+	if code := status.Status().Code; code >= 500 || apierrors.IsInternalError(err) || apierrors.IsUnexpectedServerError(err) {
+		if strings.Contains(err.Error(), "failed calling webhook") {
+			if isWebhookUnavailable(err) {
+				return ErrorClassWebhookUnavailable
+			}
+			// e.g. the webhook timed out, it may have processed the request
+			return ErrorClassUnknown
+		}
+		return ErrorClassServerError
+	}
+	return ErrorClassUnknown
+}
+
+func isWebhookUnavailable(err error) bool {
+	msg := err.Error()
+	for _, m := range webhookUnavailableMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookDeniedRegexp matches the message of a request denied by an admission
+// webhook, e.g. admission webhook "validate.example.com" denied the request: reason
+var webhookDeniedRegexp = regexp.MustCompile(`admission webhook "([^"]+)" denied the request:?\s*(.*)`)
+
+// WebhookDenial returns the name of the admission webhook that denied the
+// request and its denial message.
+func WebhookDenial(err error) (string, string, bool) {
+	if err == nil {
+		return "", "", false
+	}
+	m := webhookDeniedRegexp.FindStringSubmatch(err.Error())
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// CauseTypeFieldValueUnknown is the cause type of an unknown field rejected by
+// the Strict field validation.
+const CauseTypeFieldValueUnknown metav1.CauseType = "FieldValueUnknown"
+
+// strictDecodingRegexp matches the unknown and duplicate fields in the message of
+// a request rejected by the Strict field validation, e.g.
+// strict decoding error: unknown field "spec.replica", duplicate field "metadata.name"
+var strictDecodingRegexp = regexp.MustCompile(`(unknown|duplicate) field "([^"]+)"`)
+
+// StrictDecodingCauses returns the unknown and duplicate fields of a request
+// rejected by the Strict field validation as causes, the API server only reports
+// them in the message of the error.
+func StrictDecodingCauses(err error) []metav1.StatusCause {
+	if err == nil || !strings.Contains(err.Error(), "strict decoding error") {
+		return nil
+	}
+	var causes []metav1.StatusCause
+	for _, m := range strictDecodingRegexp.FindAllStringSubmatch(err.Error(), -1) {
+		cause := metav1.StatusCause{
+			Type:    CauseTypeFieldValueUnknown,
+			Message: "unknown field",
+			Field:   m[2],
+		}
+		if m[1] == "duplicate" {
+			cause.Type = metav1.CauseTypeFieldValueDuplicate
+			cause.Message = "duplicate field"
+		}
+		causes = append(causes, cause)
+	}
+	return causes
+}
+
+// Transient returns true when a request failing with an error of the class can
+// succeed when it is retried without any change. A terminating namespace is
+// transient as it is recreated when it is deleted and applied in the same run.
+func (r ErrorClass) Transient() bool {
+	switch r {
+	case ErrorClassTooManyRequests, ErrorClassServerTimeout, ErrorClassServerError,
+		ErrorClassWebhookUnavailable, ErrorClassNamespaceTerminating:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTransient returns true when the error is transient.
+func IsTransient(err error) bool {
+	return Classify(err).Transient()
+}
+
+// RetryAfter returns the delay the API server suggests before the request is
+// retried, e.g. from the Retry-After header of a TooManyRequests response.
+func RetryAfter(err error) (time.Duration, bool) {
+	seconds, ok := apierrors.SuggestsClientDelay(err)
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
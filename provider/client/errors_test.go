@@ -0,0 +1,233 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var deploymentGR = schema.GroupResource{Group: "apps", Resource: "deployments"}
+
+func TestClassify(t *testing.T) {
+	namespaceTerminating := apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "cm", errors.New("namespace default is being terminated"))
+	namespaceTerminating.ErrStatus.Details.Causes = []metav1.StatusCause{{Type: corev1.NamespaceTerminatingCause}}
+
+	cases := map[string]struct {
+		err       error
+		class     ErrorClass
+		transient bool
+	}{
+		"NotFound": {
+			err:   apierrors.NewNotFound(deploymentGR, "web"),
+			class: ErrorClassNotFound,
+		},
+		"AlreadyExists": {
+			err:   apierrors.NewAlreadyExists(deploymentGR, "web"),
+			class: ErrorClassAlreadyExists,
+		},
+		"Conflict": {
+			err:   apierrors.NewConflict(deploymentGR, "web", errors.New("object has been modified")),
+			class: ErrorClassConflict,
+		},
+		"Invalid": {
+			err:   apierrors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, "web", field.ErrorList{field.Required(field.NewPath("spec", "selector"), "")}),
+			class: ErrorClassInvalid,
+		},
+		"Forbidden": {
+			err:   apierrors.NewForbidden(deploymentGR, "web", errors.New("not allowed")),
+			class: ErrorClassForbidden,
+		},
+		"NamespaceTerminating": {
+			err:       namespaceTerminating,
+			class:     ErrorClassNamespaceTerminating,
+			transient: true,
+		},
+		"TooManyRequests": {
+			err:       apierrors.NewTooManyRequests("slow down", 3),
+			class:     ErrorClassTooManyRequests,
+			transient: true,
+		},
+		"ServerTimeout": {
+			err:       apierrors.NewServerTimeout(deploymentGR, "create", 1),
+			class:     ErrorClassServerTimeout,
+			transient: true,
+		},
+		"ServiceUnavailable": {
+			err:       apierrors.NewServiceUnavailable("etcd unavailable"),
+			class:     ErrorClassServerError,
+			transient: true,
+		},
+		"WebhookUnavailable": {
+			err:       apierrors.NewInternalError(errors.New(`failed calling webhook "validate.example.com": dial tcp 10.96.0.1:443: connect: connection refused`)),
+			class:     ErrorClassWebhookUnavailable,
+			transient: true,
+		},
+		// a webhook that timed out may have processed the request
+		"WebhookTimeout": {
+			err:   apierrors.NewInternalError(errors.New(`failed calling webhook "validate.example.com": context deadline exceeded`)),
+			class: ErrorClassUnknown,
+		},
+		"NoAPIError": {
+			err:   errors.New("boom"),
+			class: ErrorClassUnknown,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.class, Classify(tc.err))
+			assert.Equal(t, tc.transient, IsTransient(tc.err))
+		})
+	}
+}
+
+func TestWebhookDenial(t *testing.T) {
+	err := apierrors.NewForbidden(deploymentGR, "web", errors.New(`admission webhook "policy.example.com" denied the request: image tag latest is not allowed`))
+	name, msg, ok := WebhookDenial(err)
+	assert.True(t, ok)
+	assert.Equal(t, "policy.example.com", name)
+	assert.Equal(t, "image tag latest is not allowed", msg)
+
+	_, _, ok = WebhookDenial(apierrors.NewForbidden(deploymentGR, "web", errors.New("not allowed")))
+	assert.False(t, ok)
+}
+
+func TestStrictDecodingCauses(t *testing.T) {
+	err := apierrors.NewBadRequest(`Deployment in version "v1" cannot be handled as a Deployment: strict decoding error: unknown field "spec.replica", duplicate field "metadata.name"`)
+	assert.Equal(t, ErrorClassInvalid, Classify(err))
+	assert.Equal(t, []metav1.StatusCause{
+		{Type: CauseTypeFieldValueUnknown, Message: "unknown field", Field: "spec.replica"},
+		{Type: metav1.CauseTypeFieldValueDuplicate, Message: "duplicate field", Field: "metadata.name"},
+	}, StrictDecodingCauses(err))
+
+	assert.Empty(t, StrictDecodingCauses(apierrors.NewBadRequest("the server rejected our request")))
+}
+
+// failingClient fails the first calls of Get, Create and Update with the errors.
+type failingClient struct {
+	Client
+	errs  []error
+	calls []string
+}
+
+func (r *failingClient) call(verb string) error {
+	r.calls = append(r.calls, verb)
+	if len(r.errs) == 0 {
+		return nil
+	}
+	err := r.errs[0]
+	r.errs = r.errs[1:]
+	return err
+}
+
+func (r *failingClient) Get(_ context.Context, _ client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+	return r.call("get")
+}
+
+func (r *failingClient) Create(_ context.Context, _ client.Object, _ ...client.CreateOption) error {
+	return r.call("create")
+}
+
+func (r *failingClient) Update(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+	return r.call("update")
+}
+
+func TestWithRetry(t *testing.T) {
+	backoff := wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3}
+	key := client.ObjectKey{Namespace: "default", Name: "web"}
+
+	cases := map[string]struct {
+		create bool
+		apply  bool
+		dryRun bool
+		errs   []error
+		calls  []string
+		err    bool
+	}{
+		"Transient": {
+			errs:  []error{apierrors.NewServiceUnavailable("unavailable"), apierrors.NewServerTimeout(deploymentGR, "get", 0)},
+			calls: []string{"get", "get", "get"},
+		},
+		"Permanent": {
+			errs:  []error{apierrors.NewNotFound(deploymentGR, "web")},
+			calls: []string{"get"},
+			err:   true,
+		},
+		"Exhausted": {
+			errs:  []error{apierrors.NewServiceUnavailable("1"), apierrors.NewServiceUnavailable("2"), apierrors.NewServiceUnavailable("3"), apierrors.NewServiceUnavailable("4")},
+			calls: []string{"get", "get", "get", "get"},
+			err:   true,
+		},
+		"CreateAlreadyExists": {
+			create: true,
+			errs:   []error{apierrors.NewAlreadyExists(deploymentGR, "web")},
+			calls:  []string{"create"},
+			err:    true,
+		},
+		// the create that timed out was persisted by the API server
+		"RetriedCreateAlreadyExists": {
+			create: true,
+			errs:   []error{apierrors.NewServerTimeout(deploymentGR, "create", 0), apierrors.NewAlreadyExists(deploymentGR, "web")},
+			calls:  []string{"create", "create", "get"},
+		},
+		"RetriedDryRunCreateAlreadyExists": {
+			create: true,
+			dryRun: true,
+			errs:   []error{apierrors.NewServerTimeout(deploymentGR, "create", 0), apierrors.NewAlreadyExists(deploymentGR, "web")},
+			calls:  []string{"create", "create"},
+			err:    true,
+		},
+		// the create of the apply is retried the same way
+		"ApplyRetriedCreateAlreadyExists": {
+			apply: true,
+			errs:  []error{apierrors.NewNotFound(deploymentGR, "web"), apierrors.NewServerTimeout(deploymentGR, "create", 0), apierrors.NewAlreadyExists(deploymentGR, "web")},
+			calls: []string{"get", "create", "create", "get"},
+		},
+		"ApplyConflict": {
+			apply: true,
+			errs:  []error{nil, apierrors.NewConflict(deploymentGR, "web", errors.New("the object has been modified"))},
+			calls: []string{"get", "update"},
+			err:   true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fake := &failingClient{errs: tc.errs}
+			var err error
+			switch {
+			case tc.apply:
+				obj := &unstructured.Unstructured{}
+				obj.SetNamespace(key.Namespace)
+				obj.SetName(key.Name)
+				err = WithRetry(fake, backoff).Apply(context.Background(), obj)
+			case tc.create:
+				obj := &unstructured.Unstructured{}
+				obj.SetNamespace(key.Namespace)
+				obj.SetName(key.Name)
+				var opts []client.CreateOption
+				if tc.dryRun {
+					opts = append(opts, client.DryRunAll)
+				}
+				err = WithRetry(fake, backoff).Create(context.Background(), obj, opts...)
+			default:
+				err = WithRetry(fake, backoff).Get(context.Background(), key, &unstructured.Unstructured{})
+			}
+			if tc.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.calls, fake.calls)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FieldValidation instructs the API server how to handle unknown and duplicate
+// fields of the object on create, update and patch, one of
+// metav1.FieldValidationIgnore, metav1.FieldValidationWarn or
+// metav1.FieldValidationStrict. When empty the API server default applies.
+type FieldValidation string
+
+var (
+	_ client.CreateOption = FieldValidation("")
+	_ client.UpdateOption = FieldValidation("")
+	_ client.PatchOption  = FieldValidation("")
+)
+
+// Valid returns true when the field validation is empty or a known value.
+func (r FieldValidation) Valid() bool {
+	switch r {
+	case "", metav1.FieldValidationIgnore, metav1.FieldValidationWarn, metav1.FieldValidationStrict:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r FieldValidation) ApplyToCreate(o *client.CreateOptions) {
+	if r == "" {
+		return
+	}
+	if o.Raw == nil {
+		o.Raw = &metav1.CreateOptions{}
+	}
+	o.Raw.FieldValidation = string(r)
+}
+
+func (r FieldValidation) ApplyToUpdate(o *client.UpdateOptions) {
+	if r == "" {
+		return
+	}
+	if o.Raw == nil {
+		o.Raw = &metav1.UpdateOptions{}
+	}
+	o.Raw.FieldValidation = string(r)
+}
+
+func (r FieldValidation) ApplyToPatch(o *client.PatchOptions) {
+	if r == "" {
+		return
+	}
+	if o.Raw == nil {
+		o.Raw = &metav1.PatchOptions{}
+	}
+	o.Raw.FieldValidation = string(r)
+}
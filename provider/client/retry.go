@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultBackoff is the backoff of the requests failing with a transient error.
+var DefaultBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    5,
+	Cap:      30 * time.Second,
+}
+
+// WithRetry returns a Client that retries the requests of the client failing with
+// a transient error with the backoff, or the delay suggested by the API server
+// when it is longer. Requests failing with a permanent error return immediately.
+func WithRetry(c Client, backoff wait.Backoff) Client {
+	return &retryingClient{
+		Client:  c,
+		backoff: backoff,
+	}
+}
+
+type retryingClient struct {
+	Client
+	backoff wait.Backoff
+}
+
+// do calls fn until it succeeds, fails with a permanent error, the backoff is
+// exhausted or the context is done, it returns the last error of fn.
+func (r *retryingClient) do(ctx context.Context, fn func() error) error {
+	backoff := r.backoff
+	for {
+		err := fn()
+		if err == nil || !IsTransient(err) || backoff.Steps < 1 {
+			return err
+		}
+		delay := backoff.Step()
+		if d, ok := RetryAfter(err); ok && d > delay {
+			delay = d
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+}
+
+func (r *retryingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	return r.do(ctx, func() error { return r.Client.Get(ctx, key, obj, opts...) })
+}
+
+func (r *retryingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return r.do(ctx, func() error { return r.Client.List(ctx, list, opts...) })
+}
+
+// Create retries the create failing with a transient error. A create that timed
+// out may have been persisted by the API server, such that its retry fails with
+// AlreadyExists. The retry then succeeds and gets the object.
+func (r *retryingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	o := (&client.CreateOptions{}).ApplyOptions(opts)
+	retried := false
+	return r.do(ctx, func() error {
+		err := r.Client.Create(ctx, obj, opts...)
+		if retried && len(o.DryRun) == 0 && apierrors.IsAlreadyExists(err) {
+			return r.Client.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		}
+		retried = true
+		return err
+	})
+}
+
+func (r *retryingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return r.do(ctx, func() error { return r.Client.Update(ctx, obj, opts...) })
+}
+
+func (r *retryingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return r.do(ctx, func() error { return r.Client.Patch(ctx, obj, patch, opts...) })
+}
+
+func (r *retryingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	return r.do(ctx, func() error { return r.Client.Delete(ctx, obj, opts...) })
+}
+
+func (r *retryingClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	return r.do(ctx, func() error { return r.Client.DeleteAllOf(ctx, obj, opts...) })
+}
+
+// Apply retries the get, create and update of the apply individually, such that
+// its create is retried like Create.
+func (r *retryingClient) Apply(ctx context.Context, obj client.Object, opts ...ApplyOption) error {
+	return apply(ctx, r, obj, opts...)
+}
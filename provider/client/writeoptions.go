@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// A WriteOption is an option of both the create and the update of an object,
+// e.g. client.DryRunAll or a FieldValidation.
+type WriteOption interface {
+	client.CreateOption
+	client.UpdateOption
+}
+
+// WithWriteOptions returns a Client that passes the options to the creates and
+// updates of the client, also to the create or update made by Apply.
+func WithWriteOptions(c Client, opts ...WriteOption) Client {
+	return &writeOptionsClient{
+		Client: c,
+		opts:   opts,
+	}
+}
+
+type writeOptionsClient struct {
+	Client
+	opts []WriteOption
+}
+
+func (r *writeOptionsClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	createOpts := make([]client.CreateOption, 0, len(r.opts)+len(opts))
+	for _, opt := range r.opts {
+		createOpts = append(createOpts, opt)
+	}
+	return r.Client.Create(ctx, obj, append(createOpts, opts...)...)
+}
+
+func (r *writeOptionsClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	updateOpts := make([]client.UpdateOption, 0, len(r.opts)+len(opts))
+	for _, opt := range r.opts {
+		updateOpts = append(updateOpts, opt)
+	}
+	return r.Client.Update(ctx, obj, append(updateOpts, opts...)...)
+}
+
+func (r *writeOptionsClient) Apply(ctx context.Context, obj client.Object, opts ...ApplyOption) error {
+	return apply(ctx, r, obj, opts...)
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kform-dev/kform-sdk-go/pkg/diag"
+	"github.com/kform-dev/kform-sdk-go/pkg/schema"
+	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
+)
+
+func dataSourceKubernetesAccessReview() *schema.Resource {
+	defaultTimout := 5 * time.Minute
+	return &schema.Resource{
+		ReadContext: dataSourceKubernetesAccessReviewRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read:    &defaultTimout,
+			Default: &defaultTimout,
+		},
+	}
+}
+
+func dataSourceKubernetesAccessReviewRead(ctx context.Context, obj *schema.ResourceObject, meta interface{}) ([]byte, diag.Diagnostics) {
+	client := meta.(*Client)
+
+	review := &v1alpha1.AccessReview{}
+	if err := json.Unmarshal(obj.GetObject(), review); err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+	review.Status = v1alpha1.AccessReviewStatus{Allowed: true}
+	for _, check := range review.Spec.Checks {
+		result, err := client.reviewAccess(ctx, check)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		if !result.Allowed {
+			review.Status.Allowed = false
+			if review.Spec.FailOnDenied != nil && *review.Spec.FailOnDenied {
+				diags = append(diags, diag.DiagErrorfWithContext(
+					fmt.Sprintf("%s %s", check.Verb, check.Resource),
+					"access denied, namespace: %q, name: %q, reason: %q", check.Namespace, check.Name, result.Reason).Get())
+			}
+		}
+		review.Status.Checks = append(review.Status.Checks, *result)
+	}
+	for _, namespace := range review.Spec.RulesNamespaces {
+		rules, err := client.reviewRules(ctx, namespace)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		review.Status.Rules = append(review.Status.Rules, *rules)
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	b, err := json.Marshal(review)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	return b, nil
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/kform-dev/kform-sdk-go/pkg/diag"
+	"github.com/kform-dev/kform-sdk-go/pkg/schema"
+	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
+)
+
+func dataSourceKubernetesEvents() *schema.Resource {
+	defaultTimout := 5 * time.Minute
+	return &schema.Resource{
+		ReadContext: dataSourceKubernetesEventsRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read:    &defaultTimout,
+			Default: &defaultTimout,
+		},
+	}
+}
+
+func dataSourceKubernetesEventsRead(ctx context.Context, obj *schema.ResourceObject, meta interface{}) ([]byte, diag.Diagnostics) {
+	client := meta.(*Client)
+
+	events := &v1alpha1.Events{}
+	if err := json.Unmarshal(obj.GetObject(), events); err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	list, err := client.listEvents(ctx, events.Spec.InvolvedObject)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	limit := 0
+	if events.Spec.Limit != nil {
+		limit = *events.Spec.Limit
+	}
+	events.Status.Events = filterEvents(list, events.Spec.Types, limit)
+
+	b, err := json.Marshal(events)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	return b, nil
+}
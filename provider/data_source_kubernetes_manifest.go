@@ -8,8 +8,8 @@ import (
 	"github.com/henderiw/logger/log"
 	"github.com/kform-dev/kform-sdk-go/pkg/diag"
 	"github.com/kform-dev/kform-sdk-go/pkg/schema"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func dataSourceKubernetesManifest() *schema.Resource {
@@ -31,10 +31,11 @@ func dataSourceKubernetesManifestRead(ctx context.Context, obj *schema.ResourceO
 		return nil, diag.FromErr(err)
 	}
 
+	// the object itself is not logged as it might contain sensitive data, e.g. secrets
 	log := log.FromContext(ctx)
-	log.Info("get data", "u", u)
+	log.Info("get data", "gvk", u.GroupVersionKind().String(), "nsn", types.NamespacedName{Namespace: u.GetNamespace(), Name: u.GetName()}.String())
 
-	newObj, err := client.Get(ctx, u, metav1.GetOptions{})
+	newObj, err := client.getObject(ctx, u)
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/kform-dev/kform-sdk-go/pkg/diag"
+	"github.com/kform-dev/kform-sdk-go/pkg/schema"
+	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
+	"github.com/kform-providers/kubernetes/provider/kstatus/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kschema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func dataSourceKubernetesOwnerGraph() *schema.Resource {
+	defaultTimout := 5 * time.Minute
+	return &schema.Resource{
+		ReadContext: dataSourceKubernetesOwnerGraphRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read:    &defaultTimout,
+			Default: &defaultTimout,
+		},
+	}
+}
+
+func dataSourceKubernetesOwnerGraphRead(ctx context.Context, obj *schema.ResourceObject, meta interface{}) ([]byte, diag.Diagnostics) {
+	client := meta.(*Client)
+
+	graph := &v1alpha1.OwnerGraph{}
+	if err := json.Unmarshal(obj.GetObject(), graph); err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	root, err := client.getObject(ctx, graph.Spec.Root.Unstructured())
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, diag.FromErr(err)
+		}
+		// a missing root has no dependents
+		graph.Status.Root = &v1alpha1.OwnerGraphNode{
+			ObjectReference: graph.Spec.Root,
+			Result:          statusResult(status.NotFound()),
+		}
+		return marshalOwnerGraph(graph)
+	}
+
+	kinds := make([]kschema.GroupKind, 0, len(graph.Spec.Kinds))
+	for _, gk := range graph.Spec.Kinds {
+		kinds = append(kinds, kschema.GroupKind{Group: gk.Group, Kind: gk.Kind})
+	}
+	maxDepth := defaultOwnerGraphMaxDepth
+	if graph.Spec.MaxDepth != nil {
+		maxDepth = *graph.Spec.MaxDepth
+	}
+
+	node, err := client.ownerGraph(ctx, root, kinds, maxDepth)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	graph.Status.Root = node
+	return marshalOwnerGraph(graph)
+}
+
+func marshalOwnerGraph(graph *v1alpha1.OwnerGraph) ([]byte, diag.Diagnostics) {
+	b, err := json.Marshal(graph)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	return b, nil
+}
@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/henderiw/logger/log"
+	"github.com/kform-dev/kform-sdk-go/pkg/diag"
+	"github.com/kform-dev/kform-sdk-go/pkg/schema"
+	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func dataSourceKubernetesSecret() *schema.Resource {
+	defaultTimout := 5 * time.Minute
+	return &schema.Resource{
+		ReadContext: dataSourceKubernetesSecretRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read:    &defaultTimout,
+			Default: &defaultTimout,
+		},
+	}
+}
+
+// dataSourceKubernetesSecretRead reads a secret, the values are returned base64
+// encoded in data unless the DecodeAnnotation is set to true. The decoded values
+// are returned in stringData, values which are not valid UTF-8 are only returned
+// base64 encoded in data, such that every value is returned once. The secret is
+// marked with the SensitiveAnnotation and its content is never logged by the
+// provider, the annotation is advisory as kform does not redact the returned object.
+func dataSourceKubernetesSecretRead(ctx context.Context, obj *schema.ResourceObject, meta interface{}) ([]byte, diag.Diagnostics) {
+	client := meta.(*Client)
+
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(obj.GetObject(), u); err != nil {
+		return nil, diag.FromErr(err)
+	}
+	if u.GetAPIVersion() == "" {
+		u.SetAPIVersion("v1")
+	}
+	if u.GetKind() == "" {
+		u.SetKind("Secret")
+	}
+	if u.GroupVersionKind().GroupKind().String() != "Secret" {
+		return nil, diag.Errorf("expected kind Secret, got: %s", u.GroupVersionKind().String())
+	}
+
+	log := log.FromContext(ctx)
+	log.Info("get secret", "nsn", types.NamespacedName{Namespace: u.GetNamespace(), Name: u.GetName()}.String())
+
+	newObj, err := client.getObject(ctx, u)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	if u.GetAnnotations()[v1alpha1.DecodeAnnotation] == "true" {
+		if err := decodeSecretData(newObj); err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
+	annotations := newObj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[v1alpha1.SensitiveAnnotation] = "true"
+	newObj.SetAnnotations(annotations)
+	// managedFields are not relevant for the consumer
+	newObj.SetManagedFields(nil)
+
+	b, err := json.Marshal(newObj)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	return b, nil
+}
+
+// decodeSecretData moves the base64 encoded values of the secret data decoded
+// to stringData; values that are not valid UTF-8 are only kept in data.
+func decodeSecretData(u *unstructured.Unstructured) error {
+	data, _, err := unstructured.NestedStringMap(u.Object, "data")
+	if err != nil {
+		return fmt.Errorf("cannot get secret data: %w", err)
+	}
+	stringData := make(map[string]interface{}, len(data))
+	binaryData := map[string]interface{}{}
+	for k, v := range data {
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			// the value itself is not reported as it is sensitive
+			return fmt.Errorf("cannot decode secret data key %s: %w", k, err)
+		}
+		if utf8.Valid(b) {
+			stringData[k] = string(b)
+			continue
+		}
+		binaryData[k] = v
+	}
+	if len(binaryData) == 0 {
+		unstructured.RemoveNestedField(u.Object, "data")
+	} else if err := unstructured.SetNestedMap(u.Object, binaryData, "data"); err != nil {
+		return err
+	}
+	return unstructured.SetNestedMap(u.Object, stringData, "stringData")
+}
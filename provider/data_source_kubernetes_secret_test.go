@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	sdkschema "github.com/kform-dev/kform-sdk-go/pkg/schema"
+	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newSecret(data map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name":      "s",
+			"namespace": "default",
+		},
+	}}
+	if data != nil {
+		u.Object["data"] = data
+	}
+	return u
+}
+
+func TestDecodeSecretData(t *testing.T) {
+	binary := base64.StdEncoding.EncodeToString([]byte{0xff, 0xfe, 0x00})
+
+	cases := map[string]struct {
+		data       map[string]interface{}
+		stringData map[string]interface{}
+		expected   map[string]interface{}
+		err        bool
+	}{
+		"UTF8": {
+			data:       map[string]interface{}{"user": base64.StdEncoding.EncodeToString([]byte("admin"))},
+			stringData: map[string]interface{}{"user": "admin"},
+		},
+		"NonUTF8": {
+			data: map[string]interface{}{
+				"user": base64.StdEncoding.EncodeToString([]byte("admin")),
+				"cert": binary,
+			},
+			stringData: map[string]interface{}{"user": "admin"},
+			expected:   map[string]interface{}{"cert": binary},
+		},
+		"NoData": {
+			stringData: map[string]interface{}{},
+		},
+		"InvalidBase64": {
+			data: map[string]interface{}{"user": "not base64!"},
+			err:  true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u := newSecret(tc.data)
+			err := decodeSecretData(u)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			stringData, _, _ := unstructured.NestedMap(u.Object, "stringData")
+			assert.Equal(t, tc.stringData, stringData)
+			data, found, _ := unstructured.NestedMap(u.Object, "data")
+			assert.Equal(t, tc.expected != nil, found)
+			if tc.expected != nil {
+				assert.Equal(t, tc.expected, data)
+			}
+		})
+	}
+}
+
+func TestDataSourceKubernetesSecretRead(t *testing.T) {
+	password := base64.StdEncoding.EncodeToString([]byte("secret"))
+
+	cases := map[string]struct {
+		decode     bool
+		data       map[string]interface{}
+		stringData map[string]interface{}
+	}{
+		// the values are not recorded in plaintext in the state by default
+		"Encoded": {
+			data: map[string]interface{}{"password": password},
+		},
+		"Decoded": {
+			decode:     true,
+			stringData: map[string]interface{}{"password": "secret"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(map[schema.GroupVersionKind]meta.RESTScope{
+				{Version: "v1", Kind: "Secret"}: meta.RESTScopeNamespace,
+			}, newSecret(map[string]interface{}{"password": password}))
+
+			input := &unstructured.Unstructured{}
+			input.SetAPIVersion("v1")
+			input.SetKind("Secret")
+			input.SetNamespace("default")
+			input.SetName("s")
+			if tc.decode {
+				input.SetAnnotations(map[string]string{v1alpha1.DecodeAnnotation: "true"})
+			}
+			b, err := json.Marshal(input)
+			assert.NoError(t, err)
+			b, diags := dataSourceKubernetesSecretRead(context.Background(), &sdkschema.ResourceObject{Obj: b}, c)
+			assert.False(t, diags.HasError())
+
+			u := &unstructured.Unstructured{}
+			assert.NoError(t, json.Unmarshal(b, u))
+			assert.Equal(t, "true", u.GetAnnotations()[v1alpha1.SensitiveAnnotation])
+			data, _, _ := unstructured.NestedMap(u.Object, "data")
+			assert.Equal(t, tc.data, data)
+			stringData, _, _ := unstructured.NestedMap(u.Object, "stringData")
+			assert.Equal(t, tc.stringData, stringData)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/henderiw/logger/log"
+	"github.com/kform-dev/kform-sdk-go/pkg/diag"
+	"github.com/kform-dev/kform-sdk-go/pkg/schema"
+	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
+	"github.com/kform-providers/kubernetes/provider/kstatus/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const waitPollInterval = 2 * time.Second
+
+func dataSourceKubernetesWait() *schema.Resource {
+	defaultTimout := 5 * time.Minute
+	return &schema.Resource{
+		ReadContext: func(ctx context.Context, obj *schema.ResourceObject, meta interface{}) ([]byte, diag.Diagnostics) {
+			return dataSourceKubernetesWaitRead(ctx, obj, meta, defaultTimout)
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Read:    &defaultTimout,
+			Default: &defaultTimout,
+		},
+	}
+}
+
+func dataSourceKubernetesWaitRead(ctx context.Context, obj *schema.ResourceObject, meta interface{}, timeout time.Duration) ([]byte, diag.Diagnostics) {
+	client := meta.(*Client)
+	log := log.FromContext(ctx)
+
+	w := &v1alpha1.Wait{}
+	if err := json.Unmarshal(obj.GetObject(), w); err != nil {
+		return nil, diag.FromErr(err)
+	}
+	predicate, err := getWaitPredicate(client, w.Spec)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	u := w.Spec.Object.Unstructured()
+	gvk := u.GroupVersionKind().String()
+	nsn := types.NamespacedName{Namespace: u.GetNamespace(), Name: u.GetName()}.String()
+	log.Info("wait", "gvk", gvk, "nsn", nsn)
+
+	var newObj *unstructured.Unstructured
+	lastMsg := resultMessage(status.NotFound())
+	if err := wait.PollUntilContextTimeout(ctx, waitPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		o, err := client.getObject(ctx, u)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				lastMsg = resultMessage(status.NotFound())
+				return false, nil
+			}
+			// the kind is served once its CRD got established, the mapper is
+			// reset on the next poll
+			if apimeta.IsNoMatchError(err) {
+				lastMsg = fmt.Sprintf("kind not yet served: %s", err.Error())
+				return false, nil
+			}
+			return false, err
+		}
+		newObj = o
+		done, msg, err := predicate(ctx, o)
+		lastMsg = msg
+		return done, err
+	}); err != nil {
+		if wait.Interrupted(err) {
+			return nil, diag.Errorf("wait gvk %s nsn %s timed out after %s: %s", gvk, nsn, timeout, lastMsg)
+		}
+		return nil, diag.FromErr(fmt.Errorf("wait gvk %s nsn %s: %w", gvk, nsn, err))
+	}
+
+	b, err := json.Marshal(newObj)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	return b, nil
+}
+
+// waitPredicate returns true when the wait is satisfied, a message describing the
+// last observation and an error when the wait can never be satisfied
+type waitPredicate func(ctx context.Context, u *unstructured.Unstructured) (bool, string, error)
+
+func getWaitPredicate(client *Client, spec v1alpha1.WaitSpec) (waitPredicate, error) {
+	switch {
+	case spec.Condition != nil:
+		return conditionPredicate(*spec.Condition), nil
+	case spec.JSONPath != nil:
+		return jsonPathPredicate(*spec.JSONPath)
+	default:
+		return statusPredicate(client), nil
+	}
+}
+
+func conditionPredicate(cond v1alpha1.WaitCondition) waitPredicate {
+	expected := cond.Status
+	if expected == "" {
+		expected = metav1.ConditionTrue
+	}
+	return func(_ context.Context, u *unstructured.Unstructured) (bool, string, error) {
+		objc, err := status.GetObjectWithConditions(u.UnstructuredContent())
+		if err != nil {
+			return false, "", err
+		}
+		for _, c := range objc.Status.Conditions {
+			if c.Type == cond.Type {
+				if c.Status == expected {
+					return true, "", nil
+				}
+				return false, fmt.Sprintf("condition %s is %s, expected %s: %s", c.Type, c.Status, expected, c.Message), nil
+			}
+		}
+		return false, fmt.Sprintf("condition %s not found", cond.Type), nil
+	}
+}
+
+func jsonPathPredicate(jp v1alpha1.WaitJSONPath) (waitPredicate, error) {
+	path := strings.TrimSpace(jp.Path)
+	if !strings.HasPrefix(path, "{") {
+		path = fmt.Sprintf("{%s}", path)
+	}
+	j := jsonpath.New("wait").AllowMissingKeys(true)
+	if err := j.Parse(path); err != nil {
+		return nil, fmt.Errorf("cannot parse jsonPath %s: %w", jp.Path, err)
+	}
+	return func(_ context.Context, u *unstructured.Unstructured) (bool, string, error) {
+		buf := &bytes.Buffer{}
+		if err := j.Execute(buf, u.UnstructuredContent()); err != nil {
+			return false, "", err
+		}
+		value := buf.String()
+		if jp.Value == nil {
+			if value != "" {
+				return true, "", nil
+			}
+			return false, fmt.Sprintf("jsonPath %s returned no value", jp.Path), nil
+		}
+		if value == *jp.Value {
+			return true, "", nil
+		}
+		return false, fmt.Sprintf("jsonPath %s returned %q, expected %q", jp.Path, value, *jp.Value), nil
+	}, nil
+}
+
+func statusPredicate(client *Client) waitPredicate {
+	return func(ctx context.Context, u *unstructured.Unstructured) (bool, string, error) {
+		result, err := client.computeStatus(ctx, u)
+		if err != nil {
+			return false, "", err
+		}
+		if result.Status == metav1.ConditionTrue {
+			return true, "", nil
+		}
+		if result.Reason == status.ReasonFailed {
+			return false, "", fmt.Errorf("failed: %s", result.Message)
+		}
+		return false, resultMessage(result), nil
+	}
+}
+
+// resultMessage describes the status result of an object that is not ready
+func resultMessage(result *status.Result) string {
+	return fmt.Sprintf("%s: %s", result.Reason, result.Message)
+}
@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/kform-dev/kform-sdk-go/pkg/schema"
+	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kschema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var widgetGVK = kschema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+
+// newReadyWidget returns a widget with the Ready condition of the status and
+// a phase.
+func newReadyWidget(ready metav1.ConditionStatus, phase string) *unstructured.Unstructured {
+	u := newWidget()
+	u.Object["status"] = map[string]interface{}{
+		"phase": phase,
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": string(ready), "message": "waiting for the widget"},
+		},
+	}
+	return u
+}
+
+// waitRead reads the wait data source of the spec for the widget within 100ms.
+func waitRead(t *testing.T, spec v1alpha1.WaitSpec, widget *unstructured.Unstructured) (*unstructured.Unstructured, string) {
+	c := newTestClient(map[kschema.GroupVersionKind]meta.RESTScope{widgetGVK: meta.RESTScopeNamespace}, widget)
+	spec.Object = v1alpha1.ObjectReference{APIVersion: "example.com/v1", Kind: "Widget", Namespace: "default", Name: "w"}
+	b, err := json.Marshal(&v1alpha1.Wait{Spec: spec})
+	assert.NoError(t, err)
+
+	b, diags := dataSourceKubernetesWaitRead(context.Background(), &schema.ResourceObject{Obj: b}, c, 100*time.Millisecond)
+	if diags.HasError() {
+		return nil, diags[0].GetDetail()
+	}
+	u := &unstructured.Unstructured{}
+	assert.NoError(t, json.Unmarshal(b, u))
+	return u, ""
+}
+
+func TestWaitNotYetPresent(t *testing.T) {
+	cases := map[string]struct {
+		object  v1alpha1.ObjectReference
+		message string
+	}{
+		"NotFound": {
+			object:  v1alpha1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "missing"},
+			message: "NotFound: resource not found",
+		},
+		// the kind of a CRD that is not yet established
+		"NoMatch": {
+			object:  v1alpha1.ObjectReference{APIVersion: "example.com/v1", Kind: "Widget", Namespace: "default", Name: "w"},
+			message: "kind not yet served",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(map[kschema.GroupVersionKind]meta.RESTScope{configMapGVK: meta.RESTScopeNamespace})
+			b, err := json.Marshal(&v1alpha1.Wait{Spec: v1alpha1.WaitSpec{Object: tc.object}})
+			assert.NoError(t, err)
+
+			_, diags := dataSourceKubernetesWaitRead(context.Background(), &schema.ResourceObject{Obj: b}, c, 100*time.Millisecond)
+			assert.True(t, diags.HasError())
+			assert.Contains(t, diags[0].GetDetail(), "timed out")
+			assert.Contains(t, diags[0].GetDetail(), tc.message)
+		})
+	}
+}
+
+func TestWaitCondition(t *testing.T) {
+	cases := map[string]struct {
+		condition v1alpha1.WaitCondition
+		ready     metav1.ConditionStatus
+		err       string
+	}{
+		"Met": {
+			condition: v1alpha1.WaitCondition{Type: "Ready"},
+			ready:     metav1.ConditionTrue,
+		},
+		"NotMet": {
+			condition: v1alpha1.WaitCondition{Type: "Ready"},
+			ready:     metav1.ConditionFalse,
+			err:       "condition Ready is False, expected True: waiting for the widget",
+		},
+		"MetWithStatus": {
+			condition: v1alpha1.WaitCondition{Type: "Ready", Status: metav1.ConditionFalse},
+			ready:     metav1.ConditionFalse,
+		},
+		"NotFound": {
+			condition: v1alpha1.WaitCondition{Type: "Reconciled"},
+			ready:     metav1.ConditionTrue,
+			err:       "condition Reconciled not found",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u, err := waitRead(t, v1alpha1.WaitSpec{Condition: &tc.condition}, newReadyWidget(tc.ready, "Running"))
+			if tc.err != "" {
+				assert.Contains(t, err, "timed out")
+				assert.Contains(t, err, tc.err)
+				return
+			}
+			assert.Empty(t, err)
+			// the final object is returned
+			assert.Equal(t, "w", u.GetName())
+		})
+	}
+}
+
+func TestWaitJSONPath(t *testing.T) {
+	value := func(s string) *string { return &s }
+
+	cases := map[string]struct {
+		jsonPath v1alpha1.WaitJSONPath
+		err      string
+	}{
+		"Value": {
+			jsonPath: v1alpha1.WaitJSONPath{Path: "{.status.phase}", Value: value("Running")},
+		},
+		"ValueWithoutBraces": {
+			jsonPath: v1alpha1.WaitJSONPath{Path: ".status.phase", Value: value("Running")},
+		},
+		"OtherValue": {
+			jsonPath: v1alpha1.WaitJSONPath{Path: "{.status.phase}", Value: value("Succeeded")},
+			err:      `jsonPath {.status.phase} returned "Running", expected "Succeeded"`,
+		},
+		"AnyValue": {
+			jsonPath: v1alpha1.WaitJSONPath{Path: "{.status.phase}"},
+		},
+		"NoValue": {
+			jsonPath: v1alpha1.WaitJSONPath{Path: "{.status.endpoint}"},
+			err:      "jsonPath {.status.endpoint} returned no value",
+		},
+		"InvalidPath": {
+			jsonPath: v1alpha1.WaitJSONPath{Path: "{.status[}"},
+			err:      "cannot parse jsonPath {.status[}",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u, err := waitRead(t, v1alpha1.WaitSpec{JSONPath: &tc.jsonPath}, newReadyWidget(metav1.ConditionFalse, "Running"))
+			if tc.err != "" {
+				assert.Contains(t, err, tc.err)
+				return
+			}
+			assert.Empty(t, err)
+			assert.Equal(t, "w", u.GetName())
+		})
+	}
+}
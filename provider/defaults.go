@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// podTemplatePaths are the paths of the pod template metadata of the workload kinds.
+var podTemplatePaths = map[schema.GroupKind][]string{
+	{Group: "apps", Kind: "Deployment"}:  {"spec", "template", "metadata"},
+	{Group: "apps", Kind: "StatefulSet"}: {"spec", "template", "metadata"},
+	{Group: "apps", Kind: "DaemonSet"}:   {"spec", "template", "metadata"},
+	{Group: "apps", Kind: "ReplicaSet"}:  {"spec", "template", "metadata"},
+	{Group: "batch", Kind: "Job"}:        {"spec", "template", "metadata"},
+	{Group: "batch", Kind: "CronJob"}:    {"spec", "jobTemplate", "spec", "template", "metadata"},
+}
+
+// objectDefaults are the labels and annotations of the provider injected into every
+// object on create and update.
+type objectDefaults struct {
+	labels      map[string]string
+	annotations map[string]string
+	// podTemplates propagates the defaults into the pod templates of the workload kinds
+	podTemplates bool
+}
+
+// metadataPaths returns the paths of the metadata the defaults apply to in the object.
+func (r *objectDefaults) metadataPaths(u *unstructured.Unstructured) [][]string {
+	paths := [][]string{{"metadata"}}
+	if !r.podTemplates {
+		return paths
+	}
+	path, ok := podTemplatePaths[u.GroupVersionKind().GroupKind()]
+	if !ok {
+		return paths
+	}
+	// the pod template is never created, it is required by the workload kinds
+	if _, found, err := unstructured.NestedMap(u.Object, path[:len(path)-1]...); err != nil || !found {
+		return paths
+	}
+	return append(paths, path)
+}
+
+// apply merges the defaults into the object, the labels and annotations of the
+// manifest take precedence.
+func (r *objectDefaults) apply(u *unstructured.Unstructured) error {
+	for _, path := range r.metadataPaths(u) {
+		if err := mergeDefaults(u, append(path, "labels"), r.labels); err != nil {
+			return err
+		}
+		if err := mergeDefaults(u, append(path, "annotations"), r.annotations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// strip removes the injected defaults from the live object, such that they are
+// excluded from the drift comparison with the manifest. A default is injected when
+// the manifest does not set it and the live object has the default value, a value
+// changed outside of kform is kept as drift.
+func (r *objectDefaults) strip(live, manifest *unstructured.Unstructured) {
+	if live == nil {
+		return
+	}
+	for _, path := range r.metadataPaths(live) {
+		stripDefaults(live, manifest, append(path, "labels"), r.labels)
+		stripDefaults(live, manifest, append(path, "annotations"), r.annotations)
+	}
+}
+
+func mergeDefaults(u *unstructured.Unstructured, fields []string, defaults map[string]string) error {
+	if len(defaults) == 0 {
+		return nil
+	}
+	m, _, err := unstructured.NestedStringMap(u.Object, fields...)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		m = map[string]string{}
+	}
+	for k, v := range defaults {
+		if _, ok := m[k]; !ok {
+			m[k] = v
+		}
+	}
+	return unstructured.SetNestedStringMap(u.Object, m, fields...)
+}
+
+func stripDefaults(live, manifest *unstructured.Unstructured, fields []string, defaults map[string]string) {
+	if len(defaults) == 0 {
+		return
+	}
+	m, found, err := unstructured.NestedStringMap(live.Object, fields...)
+	if err != nil || !found {
+		return
+	}
+	desired, _, _ := unstructured.NestedStringMap(manifest.Object, fields...)
+	for k, v := range defaults {
+		if _, ok := desired[k]; ok {
+			continue
+		}
+		if m[k] == v {
+			delete(m, k)
+		}
+	}
+	if len(m) == 0 {
+		unstructured.RemoveNestedField(live.Object, fields...)
+		return
+	}
+	_ = unstructured.SetNestedStringMap(live.Object, m, fields...)
+}
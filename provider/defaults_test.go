@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/kform-providers/kubernetes/provider/kstatus/status/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var cronJobManifest = `
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+  namespace: default
+  labels:
+    team: b
+spec:
+  schedule: "0 * * * *"
+  jobTemplate:
+    spec:
+      template:
+        metadata:
+          labels:
+            app: backup
+        spec:
+          containers:
+          - name: backup
+            image: backup
+`
+
+func TestObjectDefaultsApply(t *testing.T) {
+	cases := map[string]struct {
+		podTemplates bool
+		labels       map[string]string
+		podLabels    map[string]string
+	}{
+		"Metadata": {
+			labels:    map[string]string{"team": "b", "env": "prod"},
+			podLabels: map[string]string{"app": "backup"},
+		},
+		"PodTemplates": {
+			podTemplates: true,
+			labels:       map[string]string{"team": "b", "env": "prod"},
+			podLabels:    map[string]string{"app": "backup", "team": "a", "env": "prod"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			defaults := &objectDefaults{
+				labels:       map[string]string{"team": "a", "env": "prod"},
+				annotations:  map[string]string{"owner": "platform"},
+				podTemplates: tc.podTemplates,
+			}
+			u := testutil.YamlToUnstructured(t, cronJobManifest)
+			assert.NoError(t, defaults.apply(u))
+
+			// the labels of the manifest take precedence
+			assert.Equal(t, tc.labels, u.GetLabels())
+			assert.Equal(t, map[string]string{"owner": "platform"}, u.GetAnnotations())
+			podLabels, _, _ := unstructured.NestedStringMap(u.Object, "spec", "jobTemplate", "spec", "template", "metadata", "labels")
+			assert.Equal(t, tc.podLabels, podLabels)
+		})
+	}
+}
+
+func TestObjectDefaultsStrip(t *testing.T) {
+	defaults := &objectDefaults{
+		labels:       map[string]string{"team": "a", "env": "prod"},
+		annotations:  map[string]string{"owner": "platform"},
+		podTemplates: true,
+	}
+	manifest := testutil.YamlToUnstructured(t, cronJobManifest)
+	live := manifest.DeepCopy()
+	assert.NoError(t, defaults.apply(live))
+	// the env label was changed outside of kform
+	labels := live.GetLabels()
+	labels["env"] = "dev"
+	live.SetLabels(labels)
+
+	defaults.strip(live, manifest)
+
+	// the value of the manifest is kept and the drifted value is reported
+	assert.Equal(t, map[string]string{"team": "b", "env": "dev"}, live.GetLabels())
+	assert.Empty(t, live.GetAnnotations())
+	_, found, _ := unstructured.NestedMap(live.Object, "metadata", "annotations")
+	assert.False(t, found)
+	podLabels, _, _ := unstructured.NestedStringMap(live.Object, "spec", "jobTemplate", "spec", "template", "metadata", "labels")
+	assert.Equal(t, map[string]string{"app": "backup"}, podLabels)
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/henderiw/logger/log"
+	"github.com/kform-dev/kform-sdk-go/pkg/diag"
+	"github.com/kform-providers/kubernetes/provider/deprecation"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// checkDeprecatedAPI validates the apiVersion of the object against the deprecated
+// APIs. A deprecated apiVersion served by the cluster is recorded as warning, a
+// removed apiVersion fails with its replacement. When autoConvertDeprecatedAPIs is
+// enabled a removed apiVersion that is convertible is replaced by its replacement,
+// the returned object is the object to send to the cluster.
+func (r *Client) checkDeprecatedAPI(ctx context.Context, u *unstructured.Unstructured) (*unstructured.Unstructured, diag.Diagnostics) {
+	api, ok := deprecation.Lookup(u.GroupVersionKind())
+	if !ok {
+		return u, nil
+	}
+	log := log.FromContext(ctx)
+	apiVersion := u.GetAPIVersion()
+	kind := u.GetKind()
+
+	_, err := r.getMapping(u)
+	switch {
+	case err == nil:
+		v, err := r.getServerVersion()
+		if err != nil {
+			log.Debug("cannot get server version", "err", err.Error())
+			return u, nil
+		}
+		if api.DeprecatedAt(v) {
+			recordWarning(ctx, "apiVersion %s kind %s is deprecated since Kubernetes %s and removed in %s, %s",
+				apiVersion, kind, api.DeprecatedIn, api.RemovedIn, api.Guidance())
+		}
+		return u, nil
+	case meta.IsNoMatchError(err):
+		if r.autoConvertDeprecatedAPIs && api.Convertible {
+			newu, err := api.Convert(u)
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+			recordWarning(ctx, "apiVersion %s kind %s was removed in Kubernetes %s, converted to %s",
+				apiVersion, kind, api.RemovedIn, api.Replacement)
+			return newu, nil
+		}
+		return nil, diag.Diagnostics{diag.DiagErrorfWithContext(
+			fmt.Sprintf("%s/%s", kind, u.GetName()),
+			"apiVersion %s kind %s was removed in Kubernetes %s, %s", apiVersion, kind, api.RemovedIn, api.Guidance()).Get()}
+	default:
+		// other mapping errors are reported by the request of the operation
+		return u, nil
+	}
+}
+
+// getServerVersion returns the Kubernetes minor version of the cluster, it is
+// cached for the lifetime of the client.
+func (r *Client) getServerVersion() (deprecation.MinorVersion, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.serverVersion != nil {
+		return *r.serverVersion, nil
+	}
+	info, err := r.discoveryClient.ServerVersion()
+	if err != nil {
+		return deprecation.MinorVersion{}, err
+	}
+	v, err := deprecation.ServerMinorVersion(info)
+	if err != nil {
+		return deprecation.MinorVersion{}, err
+	}
+	r.serverVersion = &v
+	return v, nil
+}
@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	sdkschema "github.com/kform-dev/kform-sdk-go/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	cronJobV1beta1GVK = schema.GroupVersionKind{Group: "batch", Version: "v1beta1", Kind: "CronJob"}
+	cronJobGVK        = schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"}
+)
+
+// newDeprecatedAPIClient returns a Client of a cluster of the minor version
+// serving the kinds with the objects.
+func newDeprecatedAPIClient(minor string, gvks []schema.GroupVersionKind, objs ...runtime.Object) *Client {
+	scopes := map[schema.GroupVersionKind]meta.RESTScope{}
+	for _, gvk := range gvks {
+		scopes[gvk] = meta.RESTScopeNamespace
+	}
+	c := newTestClient(scopes, objs...)
+	c.discoveryClient = memory.NewMemCacheClient(&fakediscovery.FakeDiscovery{
+		Fake:               &k8stesting.Fake{},
+		FakedServerVersion: &version.Info{Major: "1", Minor: minor},
+	})
+	return c
+}
+
+func newObject(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetNamespace("default")
+	u.SetName("backup")
+	return u
+}
+
+func TestCheckDeprecatedAPI(t *testing.T) {
+	cases := map[string]struct {
+		minor       string
+		served      []schema.GroupVersionKind
+		autoConvert bool
+		obj         *unstructured.Unstructured
+		apiVersion  string
+		warnings    int
+		err         string
+	}{
+		"NotDeprecated": {
+			minor:      "25",
+			served:     []schema.GroupVersionKind{cronJobGVK},
+			obj:        newObject(cronJobGVK),
+			apiVersion: "batch/v1",
+		},
+		"NotYetDeprecated": {
+			minor:      "20",
+			served:     []schema.GroupVersionKind{cronJobV1beta1GVK},
+			obj:        newObject(cronJobV1beta1GVK),
+			apiVersion: "batch/v1beta1",
+		},
+		"Warn": {
+			minor:      "21",
+			served:     []schema.GroupVersionKind{cronJobV1beta1GVK},
+			obj:        newObject(cronJobV1beta1GVK),
+			apiVersion: "batch/v1beta1",
+			warnings:   1,
+		},
+		"Fail": {
+			minor:  "25",
+			served: []schema.GroupVersionKind{cronJobGVK},
+			obj:    newObject(cronJobV1beta1GVK),
+			err:    "apiVersion batch/v1beta1 kind CronJob was removed in Kubernetes 1.25, use batch/v1 instead",
+		},
+		"Convert": {
+			minor:       "25",
+			served:      []schema.GroupVersionKind{cronJobGVK},
+			autoConvert: true,
+			obj:         newObject(cronJobV1beta1GVK),
+			apiVersion:  "batch/v1",
+			warnings:    1,
+		},
+		"NotConvertible": {
+			minor:       "16",
+			autoConvert: true,
+			obj:         newObject(schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Deployment"}),
+			err:         "apiVersion extensions/v1beta1 kind Deployment was removed in Kubernetes 1.16, use apps/v1 instead",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newDeprecatedAPIClient(tc.minor, tc.served)
+			c.autoConvertDeprecatedAPIs = tc.autoConvert
+			ctx, rec := withWarningRecorder(context.Background())
+
+			u, diags := c.checkDeprecatedAPI(ctx, tc.obj)
+			if tc.err != "" {
+				assert.True(t, diags.HasError())
+				assert.Equal(t, tc.err, diags[0].GetDetail())
+				return
+			}
+			assert.False(t, diags.HasError())
+			assert.Equal(t, tc.apiVersion, u.GetAPIVersion())
+			assert.Len(t, rec.list(), tc.warnings)
+		})
+	}
+}
+
+func TestConvertedKeepsAPIVersion(t *testing.T) {
+	b, err := json.Marshal(newObject(cronJobV1beta1GVK))
+	assert.NoError(t, err)
+
+	c := newDeprecatedAPIClient("25", []schema.GroupVersionKind{cronJobGVK})
+	c.autoConvertDeprecatedAPIs = true
+	c.defaults = &objectDefaults{}
+	created, diags := resourceKubernetesManifestCreate(context.Background(), &sdkschema.ResourceObject{Obj: b, DryRun: true}, c)
+	assert.False(t, diags.HasError())
+	// the state does not differ from the config
+	u := &unstructured.Unstructured{}
+	assert.NoError(t, json.Unmarshal(created, u))
+	assert.Equal(t, "batch/v1beta1", u.GetAPIVersion())
+
+	// the object in state is read with the replacement apiVersion
+	c = newDeprecatedAPIClient("25", []schema.GroupVersionKind{cronJobGVK}, newObject(cronJobGVK))
+	c.autoConvertDeprecatedAPIs = true
+	c.defaults = &objectDefaults{}
+	read, diags := resourceKubernetesManifestRead(context.Background(), &sdkschema.ResourceObject{Obj: b}, c)
+	assert.False(t, diags.HasError())
+	assert.NoError(t, json.Unmarshal(read, u))
+	assert.Equal(t, "batch/v1beta1", u.GetAPIVersion())
+}
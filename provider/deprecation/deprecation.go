@@ -0,0 +1,145 @@
+// Package deprecation provides the deprecated and removed API versions of the
+// built-in kinds, such that manifests using them get upgrade guidance.
+package deprecation
+
+import (
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed apis.yaml
+var apisYAML []byte
+
+// apis are the deprecated API versions by GroupVersionKind.
+var apis = mustLoad(apisYAML)
+
+// API is a deprecated API version of a kind.
+type API struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	// DeprecatedIn is the Kubernetes minor version deprecating the API, e.g. 1.16
+	DeprecatedIn MinorVersion `json:"deprecatedIn"`
+	// RemovedIn is the Kubernetes minor version no longer serving the API, e.g. 1.22
+	RemovedIn MinorVersion `json:"removedIn"`
+	// Replacement is the apiVersion replacing the API, empty when the kind has no replacement
+	Replacement string `json:"replacement,omitempty"`
+	// Convertible indicates the objects are valid in the replacement apiVersion by
+	// only changing their apiVersion.
+	Convertible bool `json:"convertible,omitempty"`
+}
+
+// GroupVersionKind returns the GroupVersionKind of the API.
+func (r *API) GroupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: r.Group, Version: r.Version, Kind: r.Kind}
+}
+
+// DeprecatedAt returns true when the API is deprecated in the Kubernetes version.
+func (r *API) DeprecatedAt(v MinorVersion) bool {
+	return !v.Less(r.DeprecatedIn)
+}
+
+// Guidance returns the upgrade guidance of the API, e.g.
+// use apps/v1 instead
+func (r *API) Guidance() string {
+	if r.Replacement == "" {
+		return "the kind has no replacement"
+	}
+	return fmt.Sprintf("use %s instead", r.Replacement)
+}
+
+// Convert returns a copy of the object with the apiVersion of the replacement,
+// it fails when the API is not convertible.
+func (r *API) Convert(u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if !r.Convertible || r.Replacement == "" {
+		return nil, fmt.Errorf("apiVersion %s kind %s cannot be converted to %s automatically",
+			r.GroupVersionKind().GroupVersion().String(), r.Kind, r.Replacement)
+	}
+	newu := u.DeepCopy()
+	newu.SetAPIVersion(r.Replacement)
+	return newu, nil
+}
+
+// Lookup returns the deprecated API of the GroupVersionKind.
+func Lookup(gvk schema.GroupVersionKind) (*API, bool) {
+	api, ok := apis[gvk]
+	return api, ok
+}
+
+func mustLoad(b []byte) map[schema.GroupVersionKind]*API {
+	var list []*API
+	if err := yaml.Unmarshal(b, &list); err != nil {
+		panic(fmt.Sprintf("cannot load deprecated apis: %s", err))
+	}
+	apis := make(map[schema.GroupVersionKind]*API, len(list))
+	for _, api := range list {
+		apis[api.GroupVersionKind()] = api
+	}
+	return apis
+}
+
+// MinorVersion is a Kubernetes minor version, e.g. 1.22
+type MinorVersion struct {
+	Major int
+	Minor int
+}
+
+// ParseMinorVersion parses a minor version, e.g. 1.22
+func ParseMinorVersion(s string) (MinorVersion, error) {
+	major, minor, ok := strings.Cut(strings.TrimPrefix(s, "v"), ".")
+	if !ok {
+		return MinorVersion{}, fmt.Errorf("invalid version %q, expected <major>.<minor>", s)
+	}
+	return parseMinorVersion(major, minor)
+}
+
+// ServerMinorVersion returns the minor version of the server version returned by
+// discovery, the minor version of some providers has a suffix, e.g. 27+
+func ServerMinorVersion(info *version.Info) (MinorVersion, error) {
+	return parseMinorVersion(info.Major, strings.TrimRight(info.Minor, "+"))
+}
+
+func parseMinorVersion(major, minor string) (MinorVersion, error) {
+	maj, err := strconv.Atoi(major)
+	if err != nil {
+		return MinorVersion{}, fmt.Errorf("invalid major version %q: %w", major, err)
+	}
+	mnr, err := strconv.Atoi(minor)
+	if err != nil {
+		return MinorVersion{}, fmt.Errorf("invalid minor version %q: %w", minor, err)
+	}
+	return MinorVersion{Major: maj, Minor: mnr}, nil
+}
+
+// Less returns true when the version is lower than the other version.
+func (r MinorVersion) Less(o MinorVersion) bool {
+	if r.Major != o.Major {
+		return r.Major < o.Major
+	}
+	return r.Minor < o.Minor
+}
+
+func (r MinorVersion) String() string {
+	return fmt.Sprintf("%d.%d", r.Major, r.Minor)
+}
+
+// UnmarshalJSON parses the version from a string, e.g. "1.22"
+func (r *MinorVersion) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return fmt.Errorf("invalid version %s, expected a string", string(b))
+	}
+	v, err := ParseMinorVersion(s)
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
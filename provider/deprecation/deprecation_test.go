@@ -0,0 +1,85 @@
+package deprecation
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+func TestLookup(t *testing.T) {
+	api, ok := Lookup(schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Deployment"})
+	assert.True(t, ok)
+	assert.Equal(t, MinorVersion{Major: 1, Minor: 16}, api.RemovedIn)
+	assert.Equal(t, "use apps/v1 instead", api.Guidance())
+	assert.False(t, api.Convertible)
+
+	_, ok = Lookup(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+	assert.False(t, ok)
+}
+
+func TestConvert(t *testing.T) {
+	api, ok := Lookup(schema.GroupVersionKind{Group: "batch", Version: "v1beta1", Kind: "CronJob"})
+	assert.True(t, ok)
+	assert.True(t, api.DeprecatedAt(MinorVersion{Major: 1, Minor: 21}))
+	assert.False(t, api.DeprecatedAt(MinorVersion{Major: 1, Minor: 20}))
+
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("batch/v1beta1")
+	u.SetKind("CronJob")
+	newu, err := api.Convert(u)
+	assert.NoError(t, err)
+	assert.Equal(t, "batch/v1", newu.GetAPIVersion())
+	assert.Equal(t, "batch/v1beta1", u.GetAPIVersion())
+
+	api, _ = Lookup(schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Ingress"})
+	_, err = api.Convert(u)
+	assert.Error(t, err)
+}
+
+// TestConvertible pins the APIs that are converted automatically, an API is only
+// convertible when its objects have the same semantics in the replacement version.
+func TestConvertible(t *testing.T) {
+	expected := []string{
+		"apiregistration.k8s.io/v1beta1, Kind=APIService",
+		"autoscaling/v2beta2, Kind=HorizontalPodAutoscaler",
+		"batch/v1beta1, Kind=CronJob",
+		"coordination.k8s.io/v1beta1, Kind=Lease",
+		"flowcontrol.apiserver.k8s.io/v1beta1, Kind=FlowSchema",
+		"flowcontrol.apiserver.k8s.io/v1beta2, Kind=FlowSchema",
+		"flowcontrol.apiserver.k8s.io/v1beta3, Kind=FlowSchema",
+		"networking.k8s.io/v1beta1, Kind=IngressClass",
+		"rbac.authorization.k8s.io/v1beta1, Kind=ClusterRole",
+		"rbac.authorization.k8s.io/v1beta1, Kind=ClusterRoleBinding",
+		"rbac.authorization.k8s.io/v1beta1, Kind=Role",
+		"rbac.authorization.k8s.io/v1beta1, Kind=RoleBinding",
+		"scheduling.k8s.io/v1beta1, Kind=PriorityClass",
+		"storage.k8s.io/v1beta1, Kind=CSIDriver",
+		"storage.k8s.io/v1beta1, Kind=CSINode",
+		"storage.k8s.io/v1beta1, Kind=CSIStorageCapacity",
+		"storage.k8s.io/v1beta1, Kind=StorageClass",
+		"storage.k8s.io/v1beta1, Kind=VolumeAttachment",
+	}
+
+	var convertible []string
+	for gvk, api := range apis {
+		if api.Convertible {
+			convertible = append(convertible, gvk.String())
+		}
+	}
+	sort.Strings(convertible)
+	assert.Equal(t, expected, convertible)
+}
+
+func TestServerMinorVersion(t *testing.T) {
+	v, err := ServerMinorVersion(&version.Info{Major: "1", Minor: "27+"})
+	assert.NoError(t, err)
+	assert.Equal(t, MinorVersion{Major: 1, Minor: 27}, v)
+	assert.True(t, MinorVersion{Major: 1, Minor: 9}.Less(v))
+
+	_, err = ServerMinorVersion(&version.Info{Major: "1", Minor: ""})
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kform-dev/kform-sdk-go/pkg/diag"
+	"github.com/kform-providers/kubernetes/provider/client"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// apiErrorDiags returns the error of a request for the object as diagnostics with
+// the object as context. Errors of the API server are prefixed with their class,
+// such that e.g. an invalid manifest is distinguished from a forbidden request.
+// The causes of the error, e.g. the invalid fields, are returned as separate
+// diagnostics with the attribute path of the field as context, such that the field
+// can be located in the manifest.
+func apiErrorDiags(u *unstructured.Unstructured, err error) diag.Diagnostics {
+	ctx := fmt.Sprintf("%s/%s", u.GetKind(), u.GetName())
+	class := client.Classify(err)
+	if class == client.ErrorClassUnknown {
+		return diag.Diagnostics{diag.DiagFromErrWithContext(ctx, err).Get()}
+	}
+
+	var diags diag.Diagnostics
+	prefix := string(class)
+	if name, msg, ok := client.WebhookDenial(err); ok {
+		prefix = fmt.Sprintf("%s: denied by admission webhook %s", class, name)
+		diags = append(diags, diag.DiagErrorfWithContext(ctx, "%s: %s", prefix, msg).Get())
+	}
+
+	causes := client.StrictDecodingCauses(err)
+	var status apierrors.APIStatus
+	if errors.As(err, &status) && status.Status().Details != nil {
+		causes = append(causes, status.Status().Details.Causes...)
+	}
+	for _, cause := range causes {
+		causeCtx := ctx
+		if path := attributePath(cause.Field); path != "" {
+			causeCtx = fmt.Sprintf("%s: %s", ctx, path)
+		}
+		detail := cause.Message
+		if cause.Type != "" {
+			detail = fmt.Sprintf("%s: %s", cause.Type, cause.Message)
+		}
+		diags = append(diags, diag.DiagErrorfWithContext(causeCtx, "%s: %s", prefix, detail).Get())
+	}
+	if len(diags) == 0 {
+		diags = append(diags, diag.DiagErrorfWithContext(ctx, "%s: %s", class, err.Error()).Get())
+	}
+	return diags
+}
+
+// attributePath returns the path of the field of a cause in the manifest, e.g.
+// spec.template.spec.containers[0].image. The API server reports the fields with
+// or without a leading dot and the keys of maps in brackets, e.g.
+// metadata.labels[app.kubernetes.io/name], such keys are returned quoted:
+// metadata.labels["app.kubernetes.io/name"].
+func attributePath(field string) string {
+	field = strings.TrimPrefix(strings.TrimSpace(field), ".")
+	var sb strings.Builder
+	for len(field) > 0 {
+		switch field[0] {
+		case '.':
+			sb.WriteByte('.')
+			field = field[1:]
+		case '[':
+			end := strings.IndexByte(field, ']')
+			if end < 0 {
+				// not a valid field path, it is returned as is
+				sb.WriteString(field)
+				return sb.String()
+			}
+			key := strings.Trim(field[1:end], `"`)
+			if isIndex(key) {
+				sb.WriteString("[" + key + "]")
+			} else {
+				sb.WriteString(fmt.Sprintf("[%q]", key))
+			}
+			field = field[end+1:]
+		default:
+			end := strings.IndexAny(field, ".[")
+			if end < 0 {
+				end = len(field)
+			}
+			sb.WriteString(field[:end])
+			field = field[end:]
+		}
+	}
+	return sb.String()
+}
+
+func isIndex(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
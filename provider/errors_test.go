@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestAttributePath(t *testing.T) {
+	cases := map[string]struct {
+		field    string
+		expected string
+	}{
+		"Empty": {},
+		"Field": {
+			field:    "spec.replicas",
+			expected: "spec.replicas",
+		},
+		"LeadingDot": {
+			field:    ".spec.replica",
+			expected: "spec.replica",
+		},
+		"Index": {
+			field:    "spec.template.spec.containers[0].image",
+			expected: "spec.template.spec.containers[0].image",
+		},
+		"Key": {
+			field:    "metadata.labels[app.kubernetes.io/name]",
+			expected: `metadata.labels["app.kubernetes.io/name"]`,
+		},
+		"QuotedKey": {
+			field:    `data["config.yaml"]`,
+			expected: `data["config.yaml"]`,
+		},
+		"Unterminated": {
+			field:    "spec.containers[0",
+			expected: "spec.containers[0",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, attributePath(tc.field))
+		})
+	}
+}
+
+func TestAPIErrorDiags(t *testing.T) {
+	gr := schema.GroupResource{Resource: "configmaps"}
+	gk := schema.GroupKind{Kind: "ConfigMap"}
+
+	type expectedDiag struct {
+		context string
+		detail  string
+	}
+	cases := map[string]struct {
+		err   error
+		diags []expectedDiag
+	}{
+		"Invalid": {
+			err: apierrors.NewInvalid(gk, "web", field.ErrorList{
+				field.Invalid(field.NewPath("metadata", "labels").Key("app.kubernetes.io/name"), "-web", "must start with an alphanumeric character"),
+				field.Required(field.NewPath("spec", "containers").Index(0).Child("image"), ""),
+			}),
+			diags: []expectedDiag{
+				{
+					context: `ConfigMap/web: metadata.labels["app.kubernetes.io/name"]`,
+					detail:  `Invalid: FieldValueInvalid: Invalid value: "-web": must start with an alphanumeric character`,
+				},
+				{
+					context: "ConfigMap/web: spec.containers[0].image",
+					detail:  "Invalid: FieldValueRequired: Required value",
+				},
+			},
+		},
+		"WebhookDenial": {
+			err: apierrors.NewForbidden(gr, "web", errors.New(`admission webhook "policy.example.com" denied the request: label team is required`)),
+			diags: []expectedDiag{
+				{
+					context: "ConfigMap/web",
+					detail:  "Forbidden: denied by admission webhook policy.example.com: label team is required",
+				},
+			},
+		},
+		"StrictDecoding": {
+			err: apierrors.NewBadRequest(`ConfigMap in version "v1" cannot be handled as a ConfigMap: strict decoding error: unknown field "spec.replica", duplicate field "metadata.name"`),
+			diags: []expectedDiag{
+				{
+					context: "ConfigMap/web: spec.replica",
+					detail:  "Invalid: FieldValueUnknown: unknown field",
+				},
+				{
+					context: "ConfigMap/web: metadata.name",
+					detail:  "Invalid: FieldValueDuplicate: duplicate field",
+				},
+			},
+		},
+		"NoCauses": {
+			err: apierrors.NewConflict(gr, "web", errors.New("the object has been modified")),
+			diags: []expectedDiag{
+				{
+					context: "ConfigMap/web",
+					detail:  `Conflict: Operation cannot be fulfilled on configmaps "web": the object has been modified`,
+				},
+			},
+		},
+		"NoAPIError": {
+			err: errors.New("connection refused"),
+			diags: []expectedDiag{
+				{
+					context: "ConfigMap/web",
+					detail:  "connection refused",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			diags := apiErrorDiags(newConfigMap("web"), tc.err)
+			assert.True(t, diags.HasError())
+			got := []expectedDiag{}
+			for _, d := range diags {
+				got = append(got, expectedDiag{context: d.GetContext(), detail: d.GetDetail()})
+			}
+			assert.Equal(t, tc.diags, got)
+		})
+	}
+}
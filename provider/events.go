@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/henderiw/logger/log"
+	"github.com/kform-dev/kform-sdk-go/pkg/diag"
+	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var (
+	coreEventGVR   = corev1.SchemeGroupVersion.WithResource("events")
+	eventsEventGVR = eventsv1.SchemeGroupVersion.WithResource("events")
+
+	// podOwnerKinds are the kinds walked to find the pods owned by an object
+	podOwnerKinds = []schema.GroupKind{
+		{Group: "apps", Kind: "ReplicaSet"},
+		{Group: "batch", Kind: "Job"},
+		{Kind: "Pod"},
+	}
+)
+
+const (
+	// maxWarningEvents is the maximum amount of warning events per object that are
+	// attached to the diagnostics of a failed operation.
+	maxWarningEvents = 3
+	eventTypeWarning = "Warning"
+)
+
+// listEvents lists the core/v1 and events.k8s.io/v1 events of the involved object sorted
+// from most to least recent. Both apis serve the same events, so they are deduplicated
+// by uid.
+func (r *Client) listEvents(ctx context.Context, ref v1alpha1.ObjectReference) ([]v1alpha1.Event, error) {
+	events := map[types.UID]v1alpha1.Event{}
+
+	coreSelector := fields.Set{"involvedObject.kind": ref.Kind, "involvedObject.name": ref.Name}
+	if ref.Namespace != "" {
+		coreSelector["involvedObject.namespace"] = ref.Namespace
+	}
+	ul, err := r.dc.Resource(coreEventGVR).Namespace(ref.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(coreSelector).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range ul.Items {
+		e := &corev1.Event{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, e); err != nil {
+			return nil, err
+		}
+		events[e.UID] = eventFromCoreEvent(e)
+	}
+
+	eventsSelector := fields.Set{"regarding.kind": ref.Kind, "regarding.name": ref.Name}
+	if ref.Namespace != "" {
+		eventsSelector["regarding.namespace"] = ref.Namespace
+	}
+	ul, err = r.dc.Resource(eventsEventGVR).Namespace(ref.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.SelectorFromSet(eventsSelector).String(),
+	})
+	if err != nil {
+		// the events.k8s.io api is not served by every cluster
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		ul = &unstructured.UnstructuredList{}
+	}
+	for _, item := range ul.Items {
+		e := &eventsv1.Event{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, e); err != nil {
+			return nil, err
+		}
+		if _, ok := events[e.UID]; !ok {
+			events[e.UID] = eventFromEventsEvent(e)
+		}
+	}
+
+	result := make([]v1alpha1.Event, 0, len(events))
+	for _, e := range events {
+		result = append(result, e)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[j].LastTimestamp.Before(&result[i].LastTimestamp)
+	})
+	return result, nil
+}
+
+// filterEvents returns the events matching the types, limited to limit events.
+// An empty types list matches all events and a limit <= 0 does not limit the events.
+func filterEvents(events []v1alpha1.Event, eventTypes []string, limit int) []v1alpha1.Event {
+	filter := sets.New(eventTypes...)
+	result := []v1alpha1.Event{}
+	for _, e := range events {
+		if filter.Len() > 0 && !filter.Has(e.Type) {
+			continue
+		}
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+func eventFromCoreEvent(e *corev1.Event) v1alpha1.Event {
+	event := v1alpha1.Event{
+		InvolvedObject: v1alpha1.ObjectReference{
+			APIVersion: e.InvolvedObject.APIVersion,
+			Kind:       e.InvolvedObject.Kind,
+			Namespace:  e.InvolvedObject.Namespace,
+			Name:       e.InvolvedObject.Name,
+		},
+		Type:           e.Type,
+		Reason:         e.Reason,
+		Message:        e.Message,
+		Count:          e.Count,
+		Source:         e.Source.Component,
+		FirstTimestamp: e.FirstTimestamp,
+		LastTimestamp:  e.LastTimestamp,
+	}
+	if event.Source == "" {
+		event.Source = e.ReportingController
+	}
+	if e.Series != nil {
+		event.Count = e.Series.Count
+		if event.LastTimestamp.IsZero() {
+			event.LastTimestamp = metav1.NewTime(e.Series.LastObservedTime.Time)
+		}
+	}
+	setEventTime(&event, e.EventTime)
+	return event
+}
+
+func eventFromEventsEvent(e *eventsv1.Event) v1alpha1.Event {
+	event := v1alpha1.Event{
+		InvolvedObject: v1alpha1.ObjectReference{
+			APIVersion: e.Regarding.APIVersion,
+			Kind:       e.Regarding.Kind,
+			Namespace:  e.Regarding.Namespace,
+			Name:       e.Regarding.Name,
+		},
+		Type:           e.Type,
+		Reason:         e.Reason,
+		Message:        e.Note,
+		Count:          e.DeprecatedCount,
+		Source:         e.ReportingController,
+		FirstTimestamp: e.DeprecatedFirstTimestamp,
+		LastTimestamp:  e.DeprecatedLastTimestamp,
+	}
+	if event.Source == "" {
+		event.Source = e.DeprecatedSource.Component
+	}
+	if e.Series != nil {
+		event.Count = e.Series.Count
+		if event.LastTimestamp.IsZero() {
+			event.LastTimestamp = metav1.NewTime(e.Series.LastObservedTime.Time)
+		}
+	}
+	setEventTime(&event, e.EventTime)
+	return event
+}
+
+// setEventTime defaults the timestamps with the eventTime, which is used by
+// events that are recorded with the events.k8s.io api
+func setEventTime(event *v1alpha1.Event, eventTime metav1.MicroTime) {
+	if event.FirstTimestamp.IsZero() {
+		event.FirstTimestamp = metav1.NewTime(eventTime.Time)
+	}
+	if event.LastTimestamp.IsZero() {
+		event.LastTimestamp = metav1.NewTime(eventTime.Time)
+	}
+}
+
+// warningEventDiags returns the most recent warning events of the object and the pods
+// it owns as warning diagnostics, such that failures like FailedScheduling or
+// ImagePullBackOff are visible in the diagnostics of a failed operation.
+func (r *Client) warningEventDiags(ctx context.Context, u *unstructured.Unstructured) diag.Diagnostics {
+	log := log.FromContext(ctx)
+
+	refs := []v1alpha1.ObjectReference{{
+		APIVersion: u.GetAPIVersion(),
+		Kind:       u.GetKind(),
+		Namespace:  u.GetNamespace(),
+		Name:       u.GetName(),
+	}}
+	if newObj, err := r.getObject(ctx, u); err == nil {
+		node, err := r.ownerGraph(ctx, newObj, podOwnerKinds, 3)
+		if err != nil {
+			log.Debug("cannot get owned pods", "err", err.Error())
+		} else {
+			refs = append(refs, getPodReferences(node)...)
+		}
+	}
+
+	var diags diag.Diagnostics
+	for _, ref := range refs {
+		events, err := r.listEvents(ctx, ref)
+		if err != nil {
+			log.Debug("cannot list events", "kind", ref.Kind, "name", ref.Name, "err", err.Error())
+			continue
+		}
+		for _, e := range filterEvents(events, []string{eventTypeWarning}, maxWarningEvents) {
+			diags = append(diags, diag.DiagWarnfWithContext(
+				fmt.Sprintf("%s/%s", ref.Kind, ref.Name),
+				"event %s: %s (x%d)", e.Reason, e.Message, max(e.Count, 1)).Get())
+		}
+	}
+	return diags
+}
+
+func getPodReferences(node *v1alpha1.OwnerGraphNode) []v1alpha1.ObjectReference {
+	refs := []v1alpha1.ObjectReference{}
+	for _, dependent := range node.Dependents {
+		if dependent.Kind == "Pod" {
+			refs = append(refs, dependent.ObjectReference)
+		}
+		refs = append(refs, getPodReferences(dependent)...)
+	}
+	return refs
+}
@@ -0,0 +1,317 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
+	"github.com/kform-providers/kubernetes/provider/client"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	coreEventGVK       = schema.GroupVersionKind{Version: "v1", Kind: "Event"}
+	eventsEventGVK     = schema.GroupVersionKind{Group: "events.k8s.io", Version: "v1", Kind: "Event"}
+	replicaSetGVK      = schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}
+	jobGVK             = schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}
+	eventsTime         = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	eventsAPINotServed = apierrors.NewNotFound(schema.GroupResource{Group: "events.k8s.io", Resource: "events"}, "")
+)
+
+// newCoreEvent returns a core/v1 event of the involved object, it was last
+// recorded the age in minutes after the eventsTime.
+func newCoreEvent(uid, kind, name, eventType, reason string, age int) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Event",
+		"metadata": map[string]interface{}{
+			"namespace": "default",
+			"name":      uid,
+			"uid":       uid,
+		},
+		"involvedObject": map[string]interface{}{
+			"kind":      kind,
+			"namespace": "default",
+			"name":      name,
+		},
+		"type":          eventType,
+		"reason":        reason,
+		"message":       reason + " of " + name,
+		"count":         int64(2),
+		"lastTimestamp": eventsTime.Add(time.Duration(age) * time.Minute).Format(time.RFC3339),
+	}}
+}
+
+// newEventsEvent returns an events.k8s.io/v1 event of the regarding object, it
+// was recorded the age in minutes after the eventsTime.
+func newEventsEvent(uid, kind, name, eventType, reason string, age int) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "events.k8s.io/v1",
+		"kind":       "Event",
+		"metadata": map[string]interface{}{
+			"namespace": "default",
+			"name":      uid,
+			"uid":       uid,
+		},
+		"regarding": map[string]interface{}{
+			"kind":      kind,
+			"namespace": "default",
+			"name":      name,
+		},
+		"type":      eventType,
+		"reason":    reason,
+		"note":      reason + " of " + name,
+		"eventTime": eventsTime.Add(time.Duration(age) * time.Minute).Format("2006-01-02T15:04:05.000000Z07:00"),
+	}}
+}
+
+// newEventsClient returns a Client with the events, the field selectors of the
+// event lists are applied like the API server does. When eventsAPIErr is set
+// the list of the events.k8s.io api fails with it.
+func newEventsClient(t *testing.T, eventsAPIErr error, objs ...runtime.Object) *Client {
+	gvks := map[schema.GroupVersionKind]meta.RESTScope{
+		coreEventGVK:   meta.RESTScopeNamespace,
+		eventsEventGVK: meta.RESTScopeNamespace,
+		replicaSetGVK:  meta.RESTScopeNamespace,
+		jobGVK:         meta.RESTScopeNamespace,
+		podGVK:         meta.RESTScopeNamespace,
+	}
+	c := newTestClient(gvks, objs...)
+	// the owned pods are found with the kinds of podOwnerKinds, which are mapped
+	// to the preferred version of their group
+	versions := []schema.GroupVersion{}
+	for gvk := range gvks {
+		versions = append(versions, gvk.GroupVersion())
+	}
+	mapper := meta.NewDefaultRESTMapper(versions)
+	for gvk, scope := range gvks {
+		mapper.Add(gvk, scope)
+	}
+	c.mapper = newResettingMapper(mapper, memory.NewMemCacheClient(&fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}}))
+	c.Client = client.New(c.dc, c.mapper)
+
+	dc := c.dc.(*fakedynamic.FakeDynamicClient)
+	dc.PrependReactor("list", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		list := action.(k8stesting.ListAction)
+		gvr := list.GetResource()
+		prefix := "involvedObject"
+		gvk := coreEventGVK
+		if gvr.Group == eventsEventGVK.Group {
+			if eventsAPIErr != nil {
+				return true, nil, eventsAPIErr
+			}
+			prefix = "regarding"
+			gvk = eventsEventGVK
+		}
+		obj, err := dc.Tracker().List(gvr, gvk, list.GetNamespace())
+		if err != nil {
+			t.Fatal(err)
+		}
+		ul := obj.(*unstructured.UnstructuredList)
+		items := []unstructured.Unstructured{}
+		for _, item := range ul.Items {
+			set := fields.Set{}
+			for _, field := range []string{"kind", "namespace", "name"} {
+				v, _, _ := unstructured.NestedString(item.Object, prefix, field)
+				set[prefix+"."+field] = v
+			}
+			if list.GetListRestrictions().Fields.Matches(set) {
+				items = append(items, item)
+			}
+		}
+		ul.Items = items
+		return true, ul, nil
+	})
+	return c
+}
+
+func TestListEvents(t *testing.T) {
+	ref := v1alpha1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "web"}
+
+	cases := map[string]struct {
+		eventsAPIErr error
+		objs         []runtime.Object
+		reasons      []string
+		err          bool
+	}{
+		"CoreAndEvents": {
+			objs: []runtime.Object{
+				newCoreEvent("1", "Pod", "web", "Normal", "Scheduled", 1),
+				newEventsEvent("2", "Pod", "web", "Warning", "BackOff", 3),
+				// the event of another object is not listed
+				newCoreEvent("3", "Pod", "db", "Warning", "Failed", 4),
+				newCoreEvent("4", "Pod", "web", "Normal", "Pulled", 2),
+			},
+			reasons: []string{"BackOff", "Pulled", "Scheduled"},
+		},
+		// both apis serve the same events
+		"Deduplicated": {
+			objs: []runtime.Object{
+				newCoreEvent("1", "Pod", "web", "Warning", "BackOff", 1),
+				newEventsEvent("1", "Pod", "web", "Warning", "BackOff", 1),
+			},
+			reasons: []string{"BackOff"},
+		},
+		// the core/v1 events are listed when events.k8s.io is not served
+		"EventsAPINotServed": {
+			eventsAPIErr: eventsAPINotServed,
+			objs: []runtime.Object{
+				newCoreEvent("1", "Pod", "web", "Warning", "BackOff", 1),
+			},
+			reasons: []string{"BackOff"},
+		},
+		"EventsAPIError": {
+			eventsAPIErr: apierrors.NewForbidden(schema.GroupResource{Group: "events.k8s.io", Resource: "events"}, "", nil),
+			err:          true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newEventsClient(t, tc.eventsAPIErr, tc.objs...)
+
+			events, err := c.listEvents(context.Background(), ref)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			reasons := []string{}
+			for _, e := range events {
+				assert.Equal(t, "web", e.InvolvedObject.Name)
+				reasons = append(reasons, e.Reason)
+			}
+			assert.Equal(t, tc.reasons, reasons)
+		})
+	}
+}
+
+func TestEventFromEventsEvent(t *testing.T) {
+	c := newEventsClient(t, nil, newEventsEvent("1", "Pod", "web", "Warning", "BackOff", 1))
+
+	events, err := c.listEvents(context.Background(), v1alpha1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "web"})
+	assert.NoError(t, err)
+	// the timestamps default to the eventTime
+	ts := metav1.NewTime(eventsTime.Add(time.Minute).Local())
+	assert.Equal(t, []v1alpha1.Event{{
+		InvolvedObject: v1alpha1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "web"},
+		Type:           "Warning",
+		Reason:         "BackOff",
+		Message:        "BackOff of web",
+		FirstTimestamp: ts,
+		LastTimestamp:  ts,
+	}}, events)
+}
+
+func TestFilterEvents(t *testing.T) {
+	events := []v1alpha1.Event{
+		{Type: "Warning", Reason: "BackOff"},
+		{Type: "Normal", Reason: "Pulled"},
+		{Type: "Warning", Reason: "Failed"},
+		{Type: "Warning", Reason: "FailedScheduling"},
+	}
+
+	cases := map[string]struct {
+		types   []string
+		limit   int
+		reasons []string
+	}{
+		"All": {
+			reasons: []string{"BackOff", "Pulled", "Failed", "FailedScheduling"},
+		},
+		"Warning": {
+			types:   []string{"Warning"},
+			reasons: []string{"BackOff", "Failed", "FailedScheduling"},
+		},
+		"Limit": {
+			limit:   2,
+			reasons: []string{"BackOff", "Pulled"},
+		},
+		"WarningLimit": {
+			types:   []string{"Warning"},
+			limit:   2,
+			reasons: []string{"BackOff", "Failed"},
+		},
+		"NoMatch": {
+			types:   []string{"Error"},
+			reasons: []string{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			reasons := []string{}
+			for _, e := range filterEvents(events, tc.types, tc.limit) {
+				reasons = append(reasons, e.Reason)
+			}
+			assert.Equal(t, tc.reasons, reasons)
+		})
+	}
+}
+
+func TestGetPodReferences(t *testing.T) {
+	node := func(kind, name string, dependents ...*v1alpha1.OwnerGraphNode) *v1alpha1.OwnerGraphNode {
+		return &v1alpha1.OwnerGraphNode{
+			ObjectReference: v1alpha1.ObjectReference{Kind: kind, Name: name},
+			Dependents:      dependents,
+		}
+	}
+	root := node("Deployment", "web",
+		node("ReplicaSet", "web-1", node("Pod", "web-1-a"), node("Pod", "web-1-b")),
+		node("ReplicaSet", "web-2", node("Pod", "web-2-a")),
+	)
+
+	assert.Equal(t, []v1alpha1.ObjectReference{
+		{Kind: "Pod", Name: "web-1-a"},
+		{Kind: "Pod", Name: "web-1-b"},
+		{Kind: "Pod", Name: "web-2-a"},
+	}, getPodReferences(root))
+	assert.Equal(t, []v1alpha1.ObjectReference{}, getPodReferences(node("Pod", "web")))
+}
+
+func TestWarningEventDiags(t *testing.T) {
+	rs := &unstructured.Unstructured{}
+	rs.SetGroupVersionKind(replicaSetGVK)
+	rs.SetNamespace("default")
+	rs.SetName("web")
+	rs.SetUID("web")
+	pod := newPod("default", "web-a", nil)
+	pod.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web", UID: types.UID("web")}})
+
+	c := newEventsClient(t, eventsAPINotServed,
+		rs, pod,
+		newCoreEvent("1", "ReplicaSet", "web", "Warning", "FailedCreate", 1),
+		newCoreEvent("2", "ReplicaSet", "web", "Normal", "SuccessfulCreate", 2),
+		// the most recent warning events of the pods are returned
+		newCoreEvent("3", "Pod", "web-a", "Warning", "FailedScheduling", 1),
+		newCoreEvent("4", "Pod", "web-a", "Warning", "BackOff", 2),
+		newCoreEvent("5", "Pod", "web-a", "Warning", "Failed", 3),
+		newCoreEvent("6", "Pod", "web-a", "Warning", "FailedMount", 4),
+	)
+
+	diags := c.warningEventDiags(context.Background(), rs)
+	assert.False(t, diags.HasError())
+	details := []string{}
+	for _, d := range diags {
+		details = append(details, d.GetContext()+" "+d.GetDetail())
+	}
+	assert.Equal(t, []string{
+		"ReplicaSet/web event FailedCreate: FailedCreate of web (x2)",
+		"Pod/web-a event FailedMount: FailedMount of web-a (x2)",
+		"Pod/web-a event Failed: Failed of web-a (x2)",
+		"Pod/web-a event BackOff: BackOff of web-a (x2)",
+	}, details)
+}
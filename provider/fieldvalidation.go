@@ -0,0 +1,22 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
+	"github.com/kform-providers/kubernetes/provider/client"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// getFieldValidation returns the field validation of the object, the annotation of
+// the object takes precedence over the field validation of the provider.
+func (r *Client) getFieldValidation(u *unstructured.Unstructured) (client.FieldValidation, error) {
+	fv, ok := u.GetAnnotations()[v1alpha1.FieldValidationAnnotation]
+	if !ok {
+		return r.fieldValidation, nil
+	}
+	if !client.FieldValidation(fv).Valid() {
+		return "", fmt.Errorf("invalid annotation %s: %q, expected Ignore, Warn or Strict", v1alpha1.FieldValidationAnnotation, fv)
+	}
+	return client.FieldValidation(fv), nil
+}
@@ -46,3 +46,14 @@ func getConditionWithStatus(conditions []Condition, conditionType string, status
 	}
 	return Condition{}, false
 }
+
+// getCondition returns the condition of the type, a condition without status is
+// returned when the condition is not found, such that it can be reported as a check.
+func getCondition(conditions []Condition, conditionType string) (Condition, bool) {
+	for _, c := range conditions {
+		if c.Type == conditionType {
+			return c, true
+		}
+	}
+	return Condition{Condition: metav1.Condition{Type: conditionType}}, false
+}
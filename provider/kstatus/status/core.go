@@ -4,18 +4,23 @@
 package status
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 )
 
 // GetConditionsFn defines the signature for functions to compute the
 // status of a built-in resource.
-type GetConditionsFn func(*unstructured.Unstructured) (*Result, error)
+type GetConditionsFn func(*unstructured.Unstructured, *Options) (*Result, error)
 
 // legacyTypes defines the mapping from GroupKind to a function that can
 // compute the status for the given resource.
@@ -24,18 +29,26 @@ var legacyTypes = map[string]GetConditionsFn{
 	"Pod":                        podConditions,
 	"Secret":                     alwaysReady,
 	"PersistentVolumeClaim":      pvcConditions,
-	"apps/StatefulSet":           stsConditions,
-	"apps/DaemonSet":             daemonsetConditions,
-	"extensions/DaemonSet":       daemonsetConditions,
-	"apps/Deployment":            deploymentConditions,
-	"extensions/Deployment":      deploymentConditions,
+	"apps/StatefulSet":           withPodFailures(stsConditions, stsPodRevision),
+	"apps/DaemonSet":             withPodFailures(daemonsetConditions, daemonsetPodRevision),
+	"extensions/DaemonSet":       withPodFailures(daemonsetConditions, daemonsetPodRevision),
+	"apps/Deployment":            withPodFailures(deploymentConditions, deploymentPodRevision),
+	"extensions/Deployment":      withPodFailures(deploymentConditions, deploymentPodRevision),
 	"apps/ReplicaSet":            replicasetConditions,
 	"extensions/ReplicaSet":      replicasetConditions,
 	"policy/PodDisruptionBudget": pdbConditions,
 	"batch/CronJob":              alwaysReady,
 	"ConfigMap":                  alwaysReady,
-	"batch/Job":                  jobConditions,
+	"batch/Job":                  withPodFailures(jobConditions, jobPodRevision),
 	"apiextensions.k8s.io/CustomResourceDefinition": crdConditions,
+	"Namespace":                           namespaceConditions,
+	"PersistentVolume":                    pvConditions,
+	"networking.k8s.io/Ingress":           ingressConditions,
+	"extensions/Ingress":                  ingressConditions,
+	"apiregistration.k8s.io/APIService":   apiServiceConditions,
+	"autoscaling/HorizontalPodAutoscaler": hpaConditions,
+	"admissionregistration.k8s.io/ValidatingWebhookConfiguration": webhookConfigurationConditions,
+	"admissionregistration.k8s.io/MutatingWebhookConfiguration":   webhookConfigurationConditions,
 }
 
 const (
@@ -66,7 +79,7 @@ func GetLegacyConditionsFn(u *unstructured.Unstructured) GetConditionsFn {
 }
 
 // alwaysReady Used for resources that are always ready
-func alwaysReady(u *unstructured.Unstructured) (*Result, error) {
+func alwaysReady(u *unstructured.Unstructured, _ *Options) (*Result, error) {
 	return ready("ready"), nil
 }
 
@@ -76,7 +89,7 @@ func alwaysReady(u *unstructured.Unstructured) (*Result, error) {
 // actually sets any Conditions. Thus, status must be computed only based on the other
 // properties under .status. We don't have any way to find out if a reconcile for a
 // StatefulSet has failed.
-func stsConditions(u *unstructured.Unstructured) (*Result, error) {
+func stsConditions(u *unstructured.Unstructured, opts *Options) (*Result, error) {
 	obj := u.UnstructuredContent()
 
 	// updateStrategy==ondelete is a user managed statefulset.
@@ -93,24 +106,24 @@ func stsConditions(u *unstructured.Unstructured) (*Result, error) {
 	statusReplicas := GetIntField(obj, ".status.replicas", 0)
 	partition := GetIntField(obj, ".spec.updateStrategy.rollingUpdate.partition", -1)
 
-	if specReplicas > statusReplicas {
+	if !opts.check("replicas", statusReplicas >= specReplicas, statusReplicas, specReplicas) {
 		msg := fmt.Sprintf("Replicas: %d/%d", statusReplicas, specReplicas)
 		return inProgress(msg), nil
 	}
 
-	if specReplicas > readyReplicas {
+	if !opts.check("readyReplicas", readyReplicas >= specReplicas, readyReplicas, specReplicas) {
 		msg := fmt.Sprintf("Ready: %d/%d", readyReplicas, specReplicas)
 		return inProgress(msg), nil
 	}
 
-	if statusReplicas > specReplicas {
+	if !opts.check("pendingTermination", statusReplicas <= specReplicas, statusReplicas-specReplicas, 0) {
 		msg := fmt.Sprintf("Pending termination: %d", statusReplicas-specReplicas)
 		return inProgress(msg), nil
 	}
 
 	// https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#partitions
 	if partition != -1 {
-		if updatedReplicas < (specReplicas - partition) {
+		if !opts.check("updatedReplicas", updatedReplicas >= specReplicas-partition, updatedReplicas, specReplicas-partition) {
 			msg := fmt.Sprintf("updated: %d/%d", updatedReplicas, specReplicas-partition)
 			return inProgress(msg), nil
 		}
@@ -119,7 +132,7 @@ func stsConditions(u *unstructured.Unstructured) (*Result, error) {
 		return ready(msg), nil
 	}
 
-	if specReplicas > currentReplicas {
+	if !opts.check("currentReplicas", currentReplicas >= specReplicas, currentReplicas, specReplicas) {
 		msg := fmt.Sprintf("current: %d/%d", currentReplicas, specReplicas)
 		return inProgress(msg), nil
 	}
@@ -127,7 +140,7 @@ func stsConditions(u *unstructured.Unstructured) (*Result, error) {
 	// Revision
 	currentRevision := GetStringField(obj, ".status.currentRevision", "")
 	updatedRevision := GetStringField(obj, ".status.updateRevision", "")
-	if currentRevision != updatedRevision {
+	if !opts.check("currentRevision", currentRevision == updatedRevision, currentRevision, updatedRevision) {
 		msg := "Waiting for updated revision to match current"
 		return inProgress(msg), nil
 	}
@@ -141,43 +154,20 @@ func stsConditions(u *unstructured.Unstructured) (*Result, error) {
 //
 // For Deployments, we look at .status.conditions as well as the other properties
 // under .status. Status will be Failed if the progress deadline has been exceeded.
-func deploymentConditions(u *unstructured.Unstructured) (*Result, error) {
+func deploymentConditions(u *unstructured.Unstructured, opts *Options) (*Result, error) {
 	obj := u.UnstructuredContent()
 
-	progressing := false
-
-	// Check if progressDeadlineSeconds is set. If not, the controller will not set
-	// the `Progressing` condition, so it will always consider a deployment to be
-	// progressing. The use of math.MaxInt32 is due to special handling in the
-	// controller:
-	// https://github.com/kubernetes/kubernetes/blob/a3ccea9d8743f2ff82e41b6c2af6dc2c41dc7b10/pkg/controller/deployment/util/deployment_util.go#L886
-	progressDeadline := GetIntField(obj, ".spec.progressDeadlineSeconds", math.MaxInt32)
-	if progressDeadline == math.MaxInt32 {
-		progressing = true
-	}
-
-	available := false
-
 	objc, err := GetObjectWithConditions(obj)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, c := range objc.Status.Conditions {
-		switch c.Type {
-		case "Progressing": // appsv1.DeploymentProgressing:
-			// https://github.com/kubernetes/kubernetes/blob/a3ccea9d8743f2ff82e41b6c2af6dc2c41dc7b10/pkg/controller/deployment/progress.go#L52
-			if c.Reason == "ProgressDeadlineExceeded" {
-				return failed(c.Message), nil
-			}
-			if c.Status == metav1.ConditionTrue && c.Reason == "NewReplicaSetAvailable" {
-				progressing = true
-			}
-		case "Available": // appsv1.DeploymentAvailable:
-			if c.Status == metav1.ConditionTrue {
-				available = true
-			}
-		}
+	// appsv1.DeploymentProgressing
+	progressing, _ := getCondition(objc.Status.Conditions, "Progressing")
+	// https://github.com/kubernetes/kubernetes/blob/a3ccea9d8743f2ff82e41b6c2af6dc2c41dc7b10/pkg/controller/deployment/progress.go#L52
+	if progressing.Reason == "ProgressDeadlineExceeded" {
+		opts.checkCondition(progressing, false, metav1.ConditionTrue)
+		return failed(progressing.Message), nil
 	}
 
 	// replicas
@@ -189,37 +179,46 @@ func deploymentConditions(u *unstructured.Unstructured) (*Result, error) {
 
 	// TODO spec.replicas zero case ??
 
-	if specReplicas > statusReplicas {
+	if !opts.check("replicas", statusReplicas >= specReplicas, statusReplicas, specReplicas) {
 		msg := fmt.Sprintf("Replicas: %d/%d", statusReplicas, specReplicas)
 		return inProgress(msg), nil
 	}
 
-	if specReplicas > updatedReplicas {
+	if !opts.check("updatedReplicas", updatedReplicas >= specReplicas, updatedReplicas, specReplicas) {
 		msg := fmt.Sprintf("Updated: %d/%d", updatedReplicas, specReplicas)
 		return inProgress(msg), nil
 	}
 
-	if statusReplicas > specReplicas {
+	if !opts.check("pendingTermination", statusReplicas <= specReplicas, statusReplicas-specReplicas, 0) {
 		msg := fmt.Sprintf("Pending termination: %d", statusReplicas-specReplicas)
 		return inProgress(msg), nil
 	}
 
-	if updatedReplicas > availableReplicas {
+	if !opts.check("availableReplicas", availableReplicas >= updatedReplicas, availableReplicas, updatedReplicas) {
 		msg := fmt.Sprintf("Available: %d/%d", availableReplicas, updatedReplicas)
 		return inProgress(msg), nil
 	}
 
-	if specReplicas > readyReplicas {
+	if !opts.check("readyReplicas", readyReplicas >= specReplicas, readyReplicas, specReplicas) {
 		msg := fmt.Sprintf("Ready: %d/%d", readyReplicas, specReplicas)
 		return inProgress(msg), nil
 	}
 
 	// check conditions
-	if !progressing {
+	// If progressDeadlineSeconds is not set, the controller will not set the
+	// `Progressing` condition, so it will always consider a deployment to be
+	// progressing. The use of math.MaxInt32 is due to special handling in the
+	// controller:
+	// https://github.com/kubernetes/kubernetes/blob/a3ccea9d8743f2ff82e41b6c2af6dc2c41dc7b10/pkg/controller/deployment/util/deployment_util.go#L886
+	progressDeadline := GetIntField(obj, ".spec.progressDeadlineSeconds", math.MaxInt32)
+	if progressDeadline != math.MaxInt32 &&
+		!opts.checkCondition(progressing, progressing.Status == metav1.ConditionTrue && progressing.Reason == "NewReplicaSetAvailable", metav1.ConditionTrue) {
 		msg := "ReplicaSet not Available"
 		return inProgress(msg), nil
 	}
-	if !available {
+	// appsv1.DeploymentAvailable
+	available, _ := getCondition(objc.Status.Conditions, "Available")
+	if !opts.checkCondition(available, available.Status == metav1.ConditionTrue, metav1.ConditionTrue) {
 		msg := "Deployment not Available"
 		return inProgress(msg), nil
 	}
@@ -229,7 +228,7 @@ func deploymentConditions(u *unstructured.Unstructured) (*Result, error) {
 }
 
 // replicasetConditions return standardized Conditions for Replicaset
-func replicasetConditions(u *unstructured.Unstructured) (*Result, error) {
+func replicasetConditions(u *unstructured.Unstructured, opts *Options) (*Result, error) {
 	obj := u.UnstructuredContent()
 
 	// Conditions
@@ -238,9 +237,9 @@ func replicasetConditions(u *unstructured.Unstructured) (*Result, error) {
 		return nil, err
 	}
 
-	for _, c := range objc.Status.Conditions {
-		// https://github.com/kubernetes/kubernetes/blob/a3ccea9d8743f2ff82e41b6c2af6dc2c41dc7b10/pkg/controller/replicaset/replica_set_utils.go
-		if c.Type == "ReplicaFailure" && c.Status == metav1.ConditionTrue {
+	// https://github.com/kubernetes/kubernetes/blob/a3ccea9d8743f2ff82e41b6c2af6dc2c41dc7b10/pkg/controller/replicaset/replica_set_utils.go
+	if c, found := getCondition(objc.Status.Conditions, "ReplicaFailure"); found {
+		if !opts.checkCondition(c, c.Status != metav1.ConditionTrue, metav1.ConditionFalse) {
 			msg := "Replica Failure condition. Check Pods"
 			return inProgress(msg), nil
 		}
@@ -253,22 +252,22 @@ func replicasetConditions(u *unstructured.Unstructured) (*Result, error) {
 	availableReplicas := GetIntField(obj, ".status.availableReplicas", 0)
 	fullyLabelledReplicas := GetIntField(obj, ".status.fullyLabeledReplicas", 0)
 
-	if specReplicas > fullyLabelledReplicas {
+	if !opts.check("fullyLabeledReplicas", fullyLabelledReplicas >= specReplicas, fullyLabelledReplicas, specReplicas) {
 		msg := fmt.Sprintf("Labelled: %d/%d", fullyLabelledReplicas, specReplicas)
 		return inProgress(msg), nil
 	}
 
-	if specReplicas > availableReplicas {
+	if !opts.check("availableReplicas", availableReplicas >= specReplicas, availableReplicas, specReplicas) {
 		msg := fmt.Sprintf("Available: %d/%d", availableReplicas, specReplicas)
 		return inProgress(msg), nil
 	}
 
-	if specReplicas > readyReplicas {
+	if !opts.check("readyReplicas", readyReplicas >= specReplicas, readyReplicas, specReplicas) {
 		msg := fmt.Sprintf("Ready: %d/%d", readyReplicas, specReplicas)
 		return inProgress(msg), nil
 	}
 
-	if statusReplicas > specReplicas {
+	if !opts.check("pendingTermination", statusReplicas <= specReplicas, statusReplicas-specReplicas, 0) {
 		msg := fmt.Sprintf("Pending termination: %d", statusReplicas-specReplicas)
 		return inProgress(msg), nil
 	}
@@ -278,13 +277,13 @@ func replicasetConditions(u *unstructured.Unstructured) (*Result, error) {
 }
 
 // daemonsetConditions return standardized Conditions for DaemonSet
-func daemonsetConditions(u *unstructured.Unstructured) (*Result, error) {
+func daemonsetConditions(u *unstructured.Unstructured, opts *Options) (*Result, error) {
 	// We check that the latest generation is equal to observed generation as
 	// part of checking generic properties but in that case, we are lenient and
 	// skip the check if those fields are unset. For daemonset, we know that if
 	// the daemonset controller has acted on a resource, these fields would not
 	// be unset. So, we ensure that here.
-	res, err := checkGenerationSet(u)
+	res, err := checkGenerationSet(u, opts)
 	if err != nil || res != nil {
 		return res, err
 	}
@@ -299,26 +298,27 @@ func daemonsetConditions(u *unstructured.Unstructured) (*Result, error) {
 	numberReady := GetIntField(obj, ".status.numberReady", 0)
 
 	if desiredNumberScheduled == -1 {
+		opts.check("desiredNumberScheduled", false, "", "set")
 		msg := "Missing .status.desiredNumberScheduled"
 		return inProgress(msg), nil
 	}
 
-	if desiredNumberScheduled > currentNumberScheduled {
+	if !opts.check("currentNumberScheduled", currentNumberScheduled >= desiredNumberScheduled, currentNumberScheduled, desiredNumberScheduled) {
 		msg := fmt.Sprintf("Current: %d/%d", currentNumberScheduled, desiredNumberScheduled)
 		return inProgress(msg), nil
 	}
 
-	if desiredNumberScheduled > updatedNumberScheduled {
+	if !opts.check("updatedNumberScheduled", updatedNumberScheduled >= desiredNumberScheduled, updatedNumberScheduled, desiredNumberScheduled) {
 		msg := fmt.Sprintf("Updated: %d/%d", updatedNumberScheduled, desiredNumberScheduled)
 		return inProgress(msg), nil
 	}
 
-	if desiredNumberScheduled > numberAvailable {
+	if !opts.check("numberAvailable", numberAvailable >= desiredNumberScheduled, numberAvailable, desiredNumberScheduled) {
 		msg := fmt.Sprintf("Available: %d/%d", numberAvailable, desiredNumberScheduled)
 		return inProgress(msg), nil
 	}
 
-	if desiredNumberScheduled > numberReady {
+	if !opts.check("numberReady", numberReady >= desiredNumberScheduled, numberReady, desiredNumberScheduled) {
 		msg := fmt.Sprintf("Ready: %d/%d", numberReady, desiredNumberScheduled)
 		return inProgress(msg), nil
 	}
@@ -330,12 +330,13 @@ func daemonsetConditions(u *unstructured.Unstructured) (*Result, error) {
 
 // checkGenerationSet checks that the metadata.generation and
 // status.observedGeneration fields are set.
-func checkGenerationSet(u *unstructured.Unstructured) (*Result, error) {
+func checkGenerationSet(u *unstructured.Unstructured, opts *Options) (*Result, error) {
 	_, found, err := unstructured.NestedInt64(u.Object, "metadata", "generation")
 	if err != nil {
 		return nil, fmt.Errorf("looking up metadata.generation from resource: %w", err)
 	}
 	if !found {
+		opts.check("generation", false, "", "set")
 		msg := fmt.Sprintf("%s metadata.generation not found", u.GetKind())
 		return inProgress(msg), nil
 	}
@@ -345,6 +346,7 @@ func checkGenerationSet(u *unstructured.Unstructured) (*Result, error) {
 		return nil, fmt.Errorf("looking up status.observedGeneration from resource: %w", err)
 	}
 	if !found {
+		opts.check("observedGeneration", false, "", "set")
 		msg := fmt.Sprintf("%s status.observedGeneration not found", u.GetKind())
 		return inProgress(msg), nil
 	}
@@ -352,20 +354,43 @@ func checkGenerationSet(u *unstructured.Unstructured) (*Result, error) {
 }
 
 // pvcConditions return standardized Conditions for PVC
-func pvcConditions(u *unstructured.Unstructured) (*Result, error) {
+func pvcConditions(u *unstructured.Unstructured, opts *Options) (*Result, error) {
 	obj := u.UnstructuredContent()
 
 	phase := GetStringField(obj, ".status.phase", "unknown")
-	if phase != "Bound" { // corev1.ClaimBound
-		msg := fmt.Sprintf("PVC is not Bound. phase: %s", phase)
+	switch phase {
+	case "Bound": // corev1.ClaimBound
+		// All ok
+		opts.check("phase", true, phase, "Bound")
+		return ready("PVC is bound"), nil
+	case "Lost": // corev1.ClaimLost
+		opts.check("phase", false, phase, "Bound")
+		msg := "PVC lost its underlying PersistentVolume"
+		return failed(msg), nil
+	}
+	// a claim of a storage class with the WaitForFirstConsumer binding mode is
+	// only bound once a pod using the claim is scheduled
+	if storageClassName := GetStringField(obj, ".spec.storageClassName", ""); storageClassName != "" && opts.Lookup != nil {
+		sc, err := opts.Lookup.Get(storageClassGVK, "", storageClassName)
+		if err == nil && GetStringField(sc.Object, ".volumeBindingMode", "") == "WaitForFirstConsumer" {
+			opts.check(fmt.Sprintf("storageClass %s volumeBindingMode", storageClassName), true, "WaitForFirstConsumer", "WaitForFirstConsumer")
+			msg := fmt.Sprintf("PVC is waiting for first consumer. storageClass: %s", storageClassName)
+			return ready(msg), nil
+		}
+	}
+	opts.check("phase", false, phase, "Bound")
+	// the pv controller sets the storage-provisioner annotation when the claim is
+	// handed over to the provisioner of the storage class
+	if provisioner := u.GetAnnotations()["volume.kubernetes.io/storage-provisioner"]; provisioner != "" {
+		msg := fmt.Sprintf("PVC is not Bound, waiting for provisioner %s. phase: %s", provisioner, phase)
 		return inProgress(msg), nil
 	}
-	// All ok
-	return ready("PVC is bound"), nil
+	msg := fmt.Sprintf("PVC is not Bound. phase: %s", phase)
+	return inProgress(msg), nil
 }
 
 // podConditions return standardized Conditions for Pod
-func podConditions(u *unstructured.Unstructured) (*Result, error) {
+func podConditions(u *unstructured.Unstructured, opts *Options) (*Result, error) {
 	obj := u.UnstructuredContent()
 	objc, err := GetObjectWithConditions(obj)
 	if err != nil {
@@ -375,29 +400,45 @@ func podConditions(u *unstructured.Unstructured) (*Result, error) {
 
 	switch phase {
 	case "Succeeded":
+		opts.check("phase", true, phase, "Running or Succeeded")
 		return ready("Pod completed"), nil
 	case "Failed":
+		opts.check("phase", false, phase, "Running or Succeeded")
 		return failed("Pod failed"), nil
 	case "Running":
-		if hasConditionWithStatus(objc.Status.Conditions, "Ready", metav1.ConditionTrue) {
+		opts.check("phase", true, phase, "Running or Succeeded")
+		c, _ := getCondition(objc.Status.Conditions, "Ready")
+		if opts.checkCondition(c, c.Status == metav1.ConditionTrue, metav1.ConditionTrue) {
 			return ready("Pod ready"), nil
 		}
 
-		containerNames, isCrashLooping, err := getCrashLoopingContainers(obj)
+		failures, err := getFailedContainers(obj)
 		if err != nil {
 			return nil, err
 		}
-		if isCrashLooping {
-			msg := fmt.Sprintf("Containers in CrashLoop state: %s", strings.Join(containerNames, ","))
+		if !opts.check("failedContainers", len(failures) == 0, strings.Join(failures, ","), "") {
+			msg := fmt.Sprintf("Containers failed: %s", strings.Join(failures, ","))
 			return failed(msg), nil
 		}
 
 		msg := "Pod is running but is not Ready"
 		return inProgress(msg), nil
 	case "Pending":
+		opts.check("phase", false, phase, "Running or Succeeded")
+		// containers that cannot be pulled or created keep the pod pending
+		failures, err := getFailedContainers(obj)
+		if err != nil {
+			return nil, err
+		}
+		if !opts.check("failedContainers", len(failures) == 0, strings.Join(failures, ","), "") {
+			msg := fmt.Sprintf("Containers failed: %s", strings.Join(failures, ","))
+			return failed(msg), nil
+		}
+
 		c, found := getConditionWithStatus(objc.Status.Conditions, "PodScheduled", metav1.ConditionFalse)
 		if found && c.Reason == "Unschedulable" {
-			if time.Now().Add(-ScheduleWindow).Before(u.GetCreationTimestamp().Time) {
+			opts.checkCondition(c, false, metav1.ConditionTrue)
+			if opts.Clock.Now().Add(-ScheduleWindow).Before(u.GetCreationTimestamp().Time) {
 				// We give the pod 15 seconds to be scheduled before we report it
 				// as unschedulable.
 				msg := "Pod has not been scheduled"
@@ -409,54 +450,241 @@ func podConditions(u *unstructured.Unstructured) (*Result, error) {
 		msg := "Pod is in the Pending phase"
 		return inProgress(msg), nil
 	default:
+		opts.check("phase", false, phase, "Running or Succeeded")
 		// If the controller hasn't observed the pod yet, there is no phase. We consider this as it
 		// still being in progress.
 		if phase == "" {
 			msg := "Pod phase not available"
 			return inProgress(msg), nil
 		}
-		return nil, fmt.Errorf("unknown phase %s", phase)
+		// the Unknown phase is reported when the node of the pod cannot be reached
+		msg := fmt.Sprintf("Pod phase: %s", phase)
+		return Unknown(msg), nil
 	}
 }
 
-func getCrashLoopingContainers(obj map[string]interface{}) ([]string, bool, error) {
-	var containerNames []string
-	css, found, err := unstructured.NestedSlice(obj, "status", "containerStatuses")
-	if !found || err != nil {
-		return containerNames, found, err
-	}
-	for _, item := range css {
-		cs := item.(map[string]interface{})
-		n, found := cs["name"]
-		if !found {
-			continue
+// failedWaitingReasons are the reasons of waiting containers that require an
+// intervention before the container can start.
+var failedWaitingReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+}
+
+// getFailedContainers returns a description of the init containers and containers
+// of the pod that failed, in the form "name (reason)".
+func getFailedContainers(obj map[string]interface{}) ([]string, error) {
+	var failures []string
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		css, found, err := unstructured.NestedSlice(obj, "status", field)
+		if err != nil {
+			return nil, err
 		}
-		name := n.(string)
-		s, found := cs["state"]
 		if !found {
 			continue
 		}
-		state := s.(map[string]interface{})
+		for _, item := range css {
+			cs, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name := GetStringField(cs, ".name", "")
+			if name == "" {
+				continue
+			}
+			if reason := getContainerFailure(cs, field == "initContainerStatuses"); reason != "" {
+				failures = append(failures, fmt.Sprintf("%s (%s)", name, reason))
+			}
+		}
+	}
+	return failures, nil
+}
 
-		ws, found := state["waiting"]
-		if !found {
-			continue
+// getContainerFailure returns the failure reason of the container status or an
+// empty string if the container did not fail.
+func getContainerFailure(cs map[string]interface{}, initContainer bool) string {
+	if reason := GetStringField(cs, ".state.waiting.reason", ""); failedWaitingReasons[reason] {
+		// a crash looping container reports the reason of the last termination,
+		// e.g. OOMKilled, in the lastState
+		if lastReason := GetStringField(cs, ".lastState.terminated.reason", ""); reason == "CrashLoopBackOff" && lastReason == "OOMKilled" {
+			return fmt.Sprintf("%s: %s", reason, lastReason)
 		}
-		waitingState := ws.(map[string]interface{})
+		return reason
+	}
+	reason := GetStringField(cs, ".state.terminated.reason", "")
+	if reason == "OOMKilled" {
+		return reason
+	}
+	// an init container that terminated with a non zero exit code blocks the pod
+	if initContainer {
+		exitCode := GetIntField(cs, ".state.terminated.exitCode", 0)
+		if exitCode != 0 {
+			if reason == "" {
+				reason = "Error"
+			}
+			return fmt.Sprintf("%s: exit code %d", reason, exitCode)
+		}
+	}
+	return ""
+}
 
-		r, found := waitingState["reason"]
-		if !found {
+// hasReadyEndpoints returns true when one of the EndpointSlices of the service
+// has a ready endpoint.
+func hasReadyEndpoints(lookup Lookup, namespace, service string) (bool, error) {
+	selector := labels.SelectorFromSet(labels.Set{"kubernetes.io/service-name": service})
+	slices, err := lookup.List(endpointSliceGVK, namespace, selector)
+	if err != nil {
+		return false, err
+	}
+	for _, slice := range slices {
+		endpoints, _, err := unstructured.NestedSlice(slice.Object, "endpoints")
+		if err != nil {
+			return false, err
+		}
+		for _, item := range endpoints {
+			endpoint, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			// a nil ready condition must be interpreted as ready
+			ready, found, err := unstructured.NestedBool(endpoint, "conditions", "ready")
+			if err != nil {
+				return false, err
+			}
+			if !found || ready {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// withPodFailures wraps the rule of a workload to report the workload as failed
+// when it is in progress and one of the pods of its current revision has failed
+// containers. The pods of previous revisions are ignored, as they are replaced by
+// the rollout that is in progress, which might be the fix of the failure. The pods
+// are retrieved with the Lookup option, the check is skipped when no Lookup is
+// provided or the current revision is not known.
+func withPodFailures(fn GetConditionsFn, revisionFn podRevisionFn) GetConditionsFn {
+	return func(u *unstructured.Unstructured, opts *Options) (*Result, error) {
+		res, err := fn(u, opts)
+		if err != nil || res.Reason != ReasonInProgress || opts.Lookup == nil {
+			return res, err
+		}
+		ls := &metav1.LabelSelector{}
+		selector, found, err := unstructured.NestedMap(u.Object, "spec", "selector")
+		if err != nil || !found {
+			return res, nil
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selector, ls); err != nil {
+			return res, nil
+		}
+		sel, err := metav1.LabelSelectorAsSelector(ls)
+		if err != nil || sel.Empty() {
+			return res, nil
+		}
+		revision, found, err := revisionFn(u, opts)
+		if err != nil || !found {
+			// the pods are a best effort hint, the workload status is still valid
+			return res, nil
+		}
+		for k, v := range revision {
+			req, err := labels.NewRequirement(k, selection.Equals, []string{v})
+			if err != nil {
+				return res, nil
+			}
+			sel = sel.Add(*req)
+		}
+		pods, err := opts.Lookup.List(podGVK, u.GetNamespace(), sel)
+		if err != nil {
+			// the pods are a best effort hint, the workload status is still valid
+			return res, nil
+		}
+		for _, pod := range pods {
+			failures, err := getFailedContainers(pod.Object)
+			if err != nil {
+				return nil, err
+			}
+			if !opts.check(fmt.Sprintf("pod %s failedContainers", pod.GetName()), len(failures) == 0, strings.Join(failures, ","), "") {
+				msg := fmt.Sprintf("Pod %s has failed containers: %s", pod.GetName(), strings.Join(failures, ","))
+				return failed(msg), nil
+			}
+		}
+		return res, nil
+	}
+}
+
+// podRevisionFn returns the labels of the pods of the current revision of a
+// workload, found is false when the current revision is not known.
+type podRevisionFn func(u *unstructured.Unstructured, opts *Options) (labels.Set, bool, error)
+
+// deploymentPodRevision returns the pod-template-hash of the newest ReplicaSet
+// of the Deployment, which is the ReplicaSet with the highest revision.
+func deploymentPodRevision(u *unstructured.Unstructured, opts *Options) (labels.Set, bool, error) {
+	ls := &metav1.LabelSelector{}
+	selector, found, err := unstructured.NestedMap(u.Object, "spec", "selector")
+	if err != nil || !found {
+		return nil, false, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selector, ls); err != nil {
+		return nil, false, err
+	}
+	sel, err := metav1.LabelSelectorAsSelector(ls)
+	if err != nil {
+		return nil, false, err
+	}
+	rss, err := opts.Lookup.List(replicaSetGVK, u.GetNamespace(), sel)
+	if err != nil {
+		return nil, false, err
+	}
+	hash := ""
+	newest := -1
+	for _, rs := range rss {
+		owner := metav1.GetControllerOfNoCopy(&rs)
+		if owner == nil || owner.Kind != u.GetKind() || owner.Name != u.GetName() {
 			continue
 		}
-		reason := r.(string)
-		if reason == "CrashLoopBackOff" {
-			containerNames = append(containerNames, name)
+		revision, err := strconv.Atoi(rs.GetAnnotations()["deployment.kubernetes.io/revision"])
+		if err != nil {
+			continue
 		}
+		if revision > newest {
+			newest = revision
+			hash = rs.GetLabels()["pod-template-hash"]
+		}
+	}
+	if hash == "" {
+		return nil, false, nil
+	}
+	return labels.Set{"pod-template-hash": hash}, true, nil
+}
+
+// stsPodRevision returns the controller-revision-hash of the update revision of
+// the StatefulSet.
+func stsPodRevision(u *unstructured.Unstructured, _ *Options) (labels.Set, bool, error) {
+	revision := GetStringField(u.Object, ".status.updateRevision", "")
+	if revision == "" {
+		return nil, false, nil
 	}
-	if len(containerNames) > 0 {
-		return containerNames, true, nil
+	return labels.Set{"controller-revision-hash": revision}, true, nil
+}
+
+// daemonsetPodRevision returns the pod-template-generation of the DaemonSet, the
+// template generation is recorded in an annotation by the api server.
+func daemonsetPodRevision(u *unstructured.Unstructured, _ *Options) (labels.Set, bool, error) {
+	generation := u.GetAnnotations()["deprecated.daemonset.template.generation"]
+	if generation == "" {
+		return nil, false, nil
 	}
-	return containerNames, false, nil
+	return labels.Set{"pod-template-generation": generation}, true, nil
+}
+
+// jobPodRevision selects all the pods of the Job, a Job has a single revision.
+func jobPodRevision(_ *unstructured.Unstructured, _ *Options) (labels.Set, bool, error) {
+	return labels.Set{}, true, nil
 }
 
 // pdbConditions computes the status for PodDisruptionBudgets. A PDB
@@ -469,7 +697,7 @@ func getCrashLoopingContainers(obj map[string]interface{}) ([]string, bool, erro
 // computing the AllowedDisruptions fails (and there are many ways
 // it can fail), but there is PR against OSS Kubernetes to address
 // this: https://github.com/kubernetes/kubernetes/pull/86929
-func pdbConditions(_ *unstructured.Unstructured) (*Result, error) {
+func pdbConditions(_ *unstructured.Unstructured, _ *Options) (*Result, error) {
 	// All ok
 	return ready("AllowedDisruptions has been computed."), nil
 }
@@ -479,7 +707,7 @@ func pdbConditions(_ *unstructured.Unstructured) (*Result, error) {
 // A job will have the InProgress status until it starts running. Then it will have the Current
 // status while the job is running and after it has been completed successfully. It
 // will have the Failed status if it the job has failed.
-func jobConditions(u *unstructured.Unstructured) (*Result, error) {
+func jobConditions(u *unstructured.Unstructured, opts *Options) (*Result, error) {
 	obj := u.UnstructuredContent()
 
 	parallelism := GetIntField(obj, ".spec.parallelism", 1)
@@ -495,48 +723,73 @@ func jobConditions(u *unstructured.Unstructured) (*Result, error) {
 	if err != nil {
 		return nil, err
 	}
-	for _, c := range objc.Status.Conditions {
-		switch c.Type {
-		case "Complete":
-			if c.Status == metav1.ConditionTrue {
-				msg := fmt.Sprintf("Job Completed. succeeded: %d/%d", succeeded, completions)
-				return ready(msg), nil
-			}
-		case "Failed":
-			if c.Status == metav1.ConditionTrue {
-				msg := fmt.Sprintf("Job Failed. failed: %d/%d", podFailed, completions)
-				return failed(msg), nil
-			}
-		}
+	if c, found := getConditionWithStatus(objc.Status.Conditions, "Failed", metav1.ConditionTrue); found {
+		opts.checkCondition(c, false, metav1.ConditionFalse)
+		msg := fmt.Sprintf("Job Failed. failed: %d/%d", podFailed, completions)
+		return failed(msg), nil
+	}
+	complete, _ := getCondition(objc.Status.Conditions, "Complete")
+	if opts.checkCondition(complete, complete.Status == metav1.ConditionTrue, metav1.ConditionTrue) {
+		msg := fmt.Sprintf("Job Completed. succeeded: %d/%d", succeeded, completions)
+		return ready(msg), nil
 	}
 
 	// replicas
-	if starttime == "" {
+	if !opts.check("startTime", starttime != "", starttime, "set") {
 		msg := "Job not started"
 		return inProgress(msg), nil
 	}
+	opts.check("succeeded", succeeded >= completions, succeeded, completions)
 	msg := fmt.Sprintf("Job in progress. success:%d, active: %d, failed: %d", succeeded, active, podFailed)
 	return inProgress(msg), nil
 }
 
 // serviceConditions return standardized Conditions for Service
-func serviceConditions(u *unstructured.Unstructured) (*Result, error) {
+//
+// A LoadBalancer service is ready when the cloud controller populated the load
+// balancer ingress points in the status. On clusters without a cloud controller
+// the ingress points are never populated, which can be accepted with the
+// AcceptPendingLoadBalancers option.
+func serviceConditions(u *unstructured.Unstructured, opts *Options) (*Result, error) {
 	obj := u.UnstructuredContent()
 
 	specType := GetStringField(obj, ".spec.type", "ClusterIP")
 	specClusterIP := GetStringField(obj, ".spec.clusterIP", "")
 
-	if specType == "LoadBalancer" {
-		if specClusterIP == "" {
+	switch specType {
+	case "ExternalName":
+		// ExternalName services are a DNS CNAME, no cluster IP or endpoints are allocated
+		msg := fmt.Sprintf("ExternalName service ready. externalName: %s", GetStringField(obj, ".spec.externalName", ""))
+		return ready(msg), nil
+	case "LoadBalancer":
+		if !opts.check("clusterIP", specClusterIP != "", specClusterIP, "set") {
 			msg := "ClusterIP not set. Service type: LoadBalancer"
 			return inProgress(msg), nil
 		}
+		addresses, err := getLoadBalancerAddresses(obj)
+		if err != nil {
+			return nil, err
+		}
+		if !opts.check("loadBalancer.ingress", len(addresses) > 0 || opts.AcceptPendingLoadBalancers, strings.Join(addresses, ","), "set") {
+			msg := "LoadBalancer ingress not set. Service type: LoadBalancer"
+			return inProgress(msg), nil
+		}
+		// the check passed with the AcceptPendingLoadBalancers option
+		if len(addresses) == 0 {
+			msg := "LoadBalancer ingress pending, accepted. Service type: LoadBalancer"
+			return ready(msg), nil
+		}
+		msg := fmt.Sprintf("LoadBalancer service ready. address: %s", strings.Join(addresses, ","))
+		return ready(msg), nil
 	}
 
+	if specClusterIP == "None" {
+		return ready("headless service ready"), nil
+	}
 	return ready("service ready"), nil
 }
 
-func crdConditions(u *unstructured.Unstructured) (*Result, error) {
+func crdConditions(u *unstructured.Unstructured, opts *Options) (*Result, error) {
 	obj := u.UnstructuredContent()
 
 	objc, err := GetObjectWithConditions(obj)
@@ -545,17 +798,207 @@ func crdConditions(u *unstructured.Unstructured) (*Result, error) {
 	}
 
 	for _, c := range objc.Status.Conditions {
-		if c.Type == "NamesAccepted" && c.Status == metav1.ConditionFalse {
+		if c.Type == "NamesAccepted" && !opts.checkCondition(c, c.Status != metav1.ConditionFalse, metav1.ConditionTrue) {
 			return failed(c.Message), nil
 		}
 		if c.Type == "Established" {
+			if opts.checkCondition(c, c.Status == metav1.ConditionTrue, metav1.ConditionTrue) {
+				return ready("CRD established"), nil
+			}
 			if c.Status == metav1.ConditionFalse && c.Reason != "Installing" {
 				return failed(c.Message), nil
 			}
-			if c.Status == metav1.ConditionTrue {
-				return ready("CRD established"), nil
-			}
 		}
 	}
 	return inProgress("installing"), nil
 }
+
+// namespaceConditions return standardized Conditions for Namespace
+func namespaceConditions(u *unstructured.Unstructured, opts *Options) (*Result, error) {
+	obj := u.UnstructuredContent()
+
+	phase := GetStringField(obj, ".status.phase", "")
+	opts.check("phase", phase == "Active", phase, "Active")
+	switch phase {
+	case "Active": // corev1.NamespaceActive
+		return ready("Namespace is active"), nil
+	case "Terminating": // corev1.NamespaceTerminating
+		return terminating(), nil
+	default:
+		msg := fmt.Sprintf("Namespace is not active. phase: %s", phase)
+		return inProgress(msg), nil
+	}
+}
+
+// pvConditions return standardized Conditions for PersistentVolume
+func pvConditions(u *unstructured.Unstructured, opts *Options) (*Result, error) {
+	obj := u.UnstructuredContent()
+
+	phase := GetStringField(obj, ".status.phase", "")
+	opts.check("phase", phase == "Available" || phase == "Bound" || phase == "Released", phase, "Available, Bound or Released")
+	switch phase {
+	case "Available", "Bound": // corev1.VolumeAvailable, corev1.VolumeBound
+		return ready(fmt.Sprintf("PV is %s", phase)), nil
+	case "Released": // corev1.VolumeReleased
+		// the claim was deleted, the volume is waiting on its reclaim policy
+		return ready("PV is released"), nil
+	case "Failed": // corev1.VolumeFailed
+		msg := GetStringField(obj, ".status.message", "PV failed")
+		return failed(msg), nil
+	default:
+		msg := fmt.Sprintf("PV is not Available. phase: %s", phase)
+		return inProgress(msg), nil
+	}
+}
+
+// ingressConditions return standardized Conditions for Ingress
+//
+// An Ingress is ready when the ingress controller populated the load balancer
+// ingress points in the status.
+func ingressConditions(u *unstructured.Unstructured, opts *Options) (*Result, error) {
+	obj := u.UnstructuredContent()
+
+	addresses, err := getLoadBalancerAddresses(obj)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.check("loadBalancer.ingress", len(addresses) > 0, strings.Join(addresses, ","), "set") {
+		msg := "Ingress has no load balancer address"
+		return inProgress(msg), nil
+	}
+	msg := fmt.Sprintf("Ingress is ready. address: %s", strings.Join(addresses, ","))
+	return ready(msg), nil
+}
+
+// getLoadBalancerAddresses returns the ip or hostname of the
+// status.loadBalancer.ingress entries
+func getLoadBalancerAddresses(obj map[string]interface{}) ([]string, error) {
+	var addresses []string
+	ingresses, found, err := unstructured.NestedSlice(obj, "status", "loadBalancer", "ingress")
+	if !found || err != nil {
+		return addresses, err
+	}
+	for _, item := range ingresses {
+		ingress, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if ip := GetStringField(ingress, "ip", ""); ip != "" {
+			addresses = append(addresses, ip)
+			continue
+		}
+		if hostname := GetStringField(ingress, "hostname", ""); hostname != "" {
+			addresses = append(addresses, hostname)
+		}
+	}
+	return addresses, nil
+}
+
+// apiServiceConditions return standardized Conditions for APIService
+//
+// The kube-aggregator sets the Available condition when the backing service
+// of the APIService responds to discovery.
+func apiServiceConditions(u *unstructured.Unstructured, opts *Options) (*Result, error) {
+	obj := u.UnstructuredContent()
+
+	objc, err := GetObjectWithConditions(obj)
+	if err != nil {
+		return nil, err
+	}
+	c, found := getCondition(objc.Status.Conditions, "Available")
+	if !found {
+		return inProgress("APIService availability not yet reported"), nil
+	}
+	if opts.checkCondition(c, c.Status == metav1.ConditionTrue, metav1.ConditionTrue) {
+		return ready("APIService is available"), nil
+	}
+	msg := fmt.Sprintf("APIService is not available. reason: %s, message: %s", c.Reason, c.Message)
+	return inProgress(msg), nil
+}
+
+// hpaConditions return standardized Conditions for HorizontalPodAutoscaler
+//
+// The autoscaling/v1 api does not expose the conditions in the status, they are
+// serialized in the autoscaling.alpha.kubernetes.io/conditions annotation.
+func hpaConditions(u *unstructured.Unstructured, opts *Options) (*Result, error) {
+	obj := u.UnstructuredContent()
+
+	objc, err := GetObjectWithConditions(obj)
+	if err != nil {
+		return nil, err
+	}
+	conditions := objc.Status.Conditions
+	if len(conditions) == 0 {
+		if annotation, ok := u.GetAnnotations()["autoscaling.alpha.kubernetes.io/conditions"]; ok {
+			if err := json.Unmarshal([]byte(annotation), &conditions); err != nil {
+				return nil, fmt.Errorf("cannot unmarshal hpa conditions annotation: %w", err)
+			}
+		}
+	}
+	if len(conditions) == 0 {
+		return inProgress("HPA conditions not yet reported"), nil
+	}
+
+	ableToScale, _ := getCondition(conditions, "AbleToScale")
+	if !opts.checkCondition(ableToScale, ableToScale.Status == metav1.ConditionTrue, metav1.ConditionTrue) {
+		msg := fmt.Sprintf("HPA is not able to scale. reason: %s, message: %s", ableToScale.Reason, ableToScale.Message)
+		return inProgress(msg), nil
+	}
+	scalingActive, found := getCondition(conditions, "ScalingActive")
+	if !found {
+		return inProgress("HPA ScalingActive condition not yet reported"), nil
+	}
+	// scaling is disabled on purpose when the target is scaled to zero
+	if !opts.checkCondition(scalingActive, scalingActive.Status == metav1.ConditionTrue || scalingActive.Reason == "ScalingDisabled", metav1.ConditionTrue) {
+		msg := fmt.Sprintf("HPA scaling is not active. reason: %s, message: %s", scalingActive.Reason, scalingActive.Message)
+		return inProgress(msg), nil
+	}
+	if scalingActive.Status != metav1.ConditionTrue {
+		return ready("HPA scaling is disabled"), nil
+	}
+	return ready("HPA is able to scale and scaling is active"), nil
+}
+
+// webhookConfigurationConditions return standardized Conditions for
+// ValidatingWebhookConfiguration and MutatingWebhookConfiguration
+//
+// The webhook configurations have no status, we validate every webhook has a
+// client config pointing to a url or a service.
+func webhookConfigurationConditions(u *unstructured.Unstructured, opts *Options) (*Result, error) {
+	obj := u.UnstructuredContent()
+
+	webhooks, _, err := unstructured.NestedSlice(obj, "webhooks")
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range webhooks {
+		webhook, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := GetStringField(webhook, "name", "")
+		url := GetStringField(webhook, "clientConfig.url", "")
+		service := GetStringField(webhook, "clientConfig.service.name", "")
+		if !opts.check(fmt.Sprintf("webhook %s clientConfig", name), url != "" || service != "", url+service, "url or service") {
+			msg := fmt.Sprintf("webhook %s has no url or service in its clientConfig", name)
+			return failed(msg), nil
+		}
+		if service == "" || opts.Lookup == nil {
+			continue
+		}
+		// a webhook served by a service without ready endpoints rejects all the
+		// requests it intercepts, or ignores them with the Ignore failurePolicy
+		namespace := GetStringField(webhook, "clientConfig.service.namespace", "")
+		hasEndpoints, err := hasReadyEndpoints(opts.Lookup, namespace, service)
+		if err != nil {
+			// the endpoints are a best effort check, the configuration is still valid
+			continue
+		}
+		if !opts.check(fmt.Sprintf("webhook %s service %s/%s endpoints", name, namespace, service), hasEndpoints, hasEndpoints, true) {
+			msg := fmt.Sprintf("webhook %s service %s/%s has no ready endpoints", name, namespace, service)
+			return inProgress(msg), nil
+		}
+	}
+	msg := fmt.Sprintf("%s is configured. webhooks: %d", u.GetKind(), len(webhooks))
+	return ready(msg), nil
+}
@@ -0,0 +1,857 @@
+package status
+
+import (
+	"testing"
+
+	"github.com/kform-providers/kubernetes/provider/kstatus/status/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var ingressReadyManifest = `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+  namespace: default
+  generation: 1
+spec:
+  rules:
+  - host: web.example.com
+status:
+  loadBalancer:
+    ingress:
+    - ip: 172.18.0.10
+    - hostname: lb.example.com
+`
+
+var ingressPendingManifest = `
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+  namespace: default
+  generation: 1
+spec:
+  rules:
+  - host: web.example.com
+status:
+  loadBalancer: {}
+`
+
+var namespaceActiveManifest = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: app
+status:
+  phase: Active
+`
+
+var namespaceTerminatingManifest = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: app
+status:
+  phase: Terminating
+`
+
+var pvBoundManifest = `
+apiVersion: v1
+kind: PersistentVolume
+metadata:
+  name: pv0001
+status:
+  phase: Bound
+`
+
+var pvFailedManifest = `
+apiVersion: v1
+kind: PersistentVolume
+metadata:
+  name: pv0001
+status:
+  phase: Failed
+  message: recycler failed to delete the volume
+`
+
+var pvcProvisioningManifest = `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  namespace: default
+  annotations:
+    volume.kubernetes.io/storage-provisioner: rancher.io/local-path
+spec:
+  storageClassName: local-path
+status:
+  phase: Pending
+`
+
+var pvcLostManifest = `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  namespace: default
+status:
+  phase: Lost
+`
+
+var apiServiceAvailableManifest = `
+apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: v1beta1.metrics.k8s.io
+status:
+  conditions:
+  - type: Available
+    status: "True"
+    reason: Passed
+    message: all checks passed
+`
+
+var apiServiceUnavailableManifest = `
+apiVersion: apiregistration.k8s.io/v1
+kind: APIService
+metadata:
+  name: v1beta1.metrics.k8s.io
+status:
+  conditions:
+  - type: Available
+    status: "False"
+    reason: MissingEndpoints
+    message: endpoints for service/metrics-server in "kube-system" have no addresses
+`
+
+var hpaReadyManifest = `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+  namespace: default
+status:
+  conditions:
+  - type: AbleToScale
+    status: "True"
+    reason: ReadyForNewScale
+  - type: ScalingActive
+    status: "True"
+    reason: ValidMetricFound
+`
+
+var hpaMissingMetricsManifest = `
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+  namespace: default
+status:
+  conditions:
+  - type: AbleToScale
+    status: "True"
+    reason: SucceededGetScale
+  - type: ScalingActive
+    status: "False"
+    reason: FailedGetResourceMetric
+    message: unable to get metrics for resource cpu
+`
+
+var hpaV1ReadyManifest = `
+apiVersion: autoscaling/v1
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+  namespace: default
+  annotations:
+    autoscaling.alpha.kubernetes.io/conditions: '[{"type":"AbleToScale","status":"True","lastTransitionTime":"2024-03-29T00:58:41Z","reason":"ReadyForNewScale"},{"type":"ScalingActive","status":"True","lastTransitionTime":"2024-03-29T00:58:41Z","reason":"ValidMetricFound"}]'
+`
+
+var webhookConfigurationManifest = `
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: policy
+webhooks:
+- name: validate.policy.example.com
+  clientConfig:
+    service:
+      name: policy-webhook
+      namespace: policy-system
+- name: audit.policy.example.com
+  clientConfig:
+    url: https://audit.example.com/validate
+`
+
+var webhookConfigurationInvalidManifest = `
+apiVersion: admissionregistration.k8s.io/v1
+kind: MutatingWebhookConfiguration
+metadata:
+  name: policy
+webhooks:
+- name: mutate.policy.example.com
+  clientConfig: {}
+`
+
+var serviceLoadBalancerReadyManifest = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: default
+spec:
+  type: LoadBalancer
+  clusterIP: 10.96.0.10
+status:
+  loadBalancer:
+    ingress:
+    - ip: 172.18.0.20
+`
+
+var serviceLoadBalancerPendingManifest = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: default
+spec:
+  type: LoadBalancer
+  clusterIP: 10.96.0.10
+status:
+  loadBalancer: {}
+`
+
+var serviceExternalNameManifest = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  namespace: default
+spec:
+  type: ExternalName
+  externalName: db.example.com
+`
+
+var serviceHeadlessManifest = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  namespace: default
+spec:
+  clusterIP: None
+`
+
+var podImagePullBackOffManifest = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web-7d4b9c
+  namespace: default
+  labels:
+    app: web
+    pod-template-hash: 7d4b9c
+status:
+  phase: Pending
+  containerStatuses:
+  - name: web
+    ready: false
+    state:
+      waiting:
+        reason: ImagePullBackOff
+        message: Back-off pulling image "nginx:does-not-exist"
+`
+
+var podCreateContainerConfigErrorManifest = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  namespace: default
+status:
+  phase: Pending
+  containerStatuses:
+  - name: web
+    ready: false
+    state:
+      waiting:
+        reason: CreateContainerConfigError
+        message: secret "web-config" not found
+`
+
+var podOOMKilledManifest = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  namespace: default
+status:
+  phase: Running
+  conditions:
+  - type: Ready
+    status: "False"
+  containerStatuses:
+  - name: web
+    ready: false
+    restartCount: 3
+    state:
+      waiting:
+        reason: CrashLoopBackOff
+    lastState:
+      terminated:
+        reason: OOMKilled
+        exitCode: 137
+`
+
+var podInitContainerFailedManifest = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  namespace: default
+status:
+  phase: Pending
+  initContainerStatuses:
+  - name: migrate
+    ready: false
+    state:
+      terminated:
+        reason: Error
+        exitCode: 1
+  containerStatuses:
+  - name: web
+    ready: false
+    state:
+      waiting:
+        reason: PodInitializing
+`
+
+var deploymentInProgressManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+  generation: 1
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: web
+status:
+  observedGeneration: 1
+  replicas: 1
+  updatedReplicas: 1
+  unavailableReplicas: 1
+`
+
+var replicaSetNewRevisionManifest = `
+apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: web-7d4b9c
+  namespace: default
+  labels:
+    app: web
+    pod-template-hash: 7d4b9c
+  annotations:
+    deployment.kubernetes.io/revision: "2"
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: Deployment
+    name: web
+    uid: 4f0c2a3e-8d1b-4c7a-9e2f-1a2b3c4d5e6f
+    controller: true
+`
+
+var replicaSetOldRevisionManifest = `
+apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: web-5f6c8d
+  namespace: default
+  labels:
+    app: web
+    pod-template-hash: 5f6c8d
+  annotations:
+    deployment.kubernetes.io/revision: "1"
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: Deployment
+    name: web
+    uid: 4f0c2a3e-8d1b-4c7a-9e2f-1a2b3c4d5e6f
+    controller: true
+`
+
+var podOldRevisionFailedManifest = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web-5f6c8d-q7x2m
+  namespace: default
+  labels:
+    app: web
+    pod-template-hash: 5f6c8d
+status:
+  phase: Running
+  containerStatuses:
+  - name: web
+    ready: false
+    state:
+      waiting:
+        reason: CrashLoopBackOff
+`
+
+var podNewRevisionRunningManifest = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web-7d4b9c-k4z8p
+  namespace: default
+  labels:
+    app: web
+    pod-template-hash: 7d4b9c
+status:
+  phase: Running
+  containerStatuses:
+  - name: web
+    ready: false
+    state:
+      running:
+        startedAt: "2024-03-29T00:59:00Z"
+`
+
+var statefulSetInProgressManifest = `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+  namespace: default
+  generation: 2
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: db
+status:
+  observedGeneration: 2
+  replicas: 2
+  readyReplicas: 1
+  currentRevision: db-6c9f7d8b5f
+  updateRevision: db-7b8d9c6f4d
+`
+
+var podStatefulSetOldRevisionFailedManifest = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: db-1
+  namespace: default
+  labels:
+    app: db
+    controller-revision-hash: db-6c9f7d8b5f
+status:
+  phase: Running
+  containerStatuses:
+  - name: db
+    ready: false
+    state:
+      waiting:
+        reason: CrashLoopBackOff
+`
+
+var podStatefulSetNewRevisionFailedManifest = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: db-1
+  namespace: default
+  labels:
+    app: db
+    controller-revision-hash: db-7b8d9c6f4d
+status:
+  phase: Pending
+  containerStatuses:
+  - name: db
+    ready: false
+    state:
+      waiting:
+        reason: ErrImagePull
+`
+
+var storageClassWaitForFirstConsumerManifest = `
+apiVersion: storage.k8s.io/v1
+kind: StorageClass
+metadata:
+  name: local-path
+provisioner: rancher.io/local-path
+volumeBindingMode: WaitForFirstConsumer
+`
+
+var pvcWaitForFirstConsumerManifest = `
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  namespace: default
+spec:
+  storageClassName: local-path
+status:
+  phase: Pending
+`
+
+var endpointSliceReadyManifest = `
+apiVersion: discovery.k8s.io/v1
+kind: EndpointSlice
+metadata:
+  name: policy-webhook-abcde
+  namespace: policy-system
+  labels:
+    kubernetes.io/service-name: policy-webhook
+addressType: IPv4
+endpoints:
+- addresses:
+  - 10.244.0.12
+  conditions:
+    ready: true
+`
+
+var webhookConfigurationNoEndpointsManifest = `
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: policy
+webhooks:
+- name: validate.policy.example.com
+  clientConfig:
+    service:
+      name: other-webhook
+      namespace: policy-system
+`
+
+func TestComputeCore(t *testing.T) {
+	lookup := testutil.NewFakeLookup(t,
+		replicaSetNewRevisionManifest,
+		podImagePullBackOffManifest,
+		storageClassWaitForFirstConsumerManifest,
+		endpointSliceReadyManifest,
+	)
+	cases := map[string]struct {
+		yaml   string
+		opts   []Option
+		result *Result
+	}{
+		"PodImagePullBackOff": {
+			yaml: podImagePullBackOffManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonFailed,
+				Message: "Containers failed: web (ImagePullBackOff)",
+			},
+		},
+		"PodCreateContainerConfigError": {
+			yaml: podCreateContainerConfigErrorManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonFailed,
+				Message: "Containers failed: web (CreateContainerConfigError)",
+			},
+		},
+		"PodOOMKilled": {
+			yaml: podOOMKilledManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonFailed,
+				Message: "Containers failed: web (CrashLoopBackOff: OOMKilled)",
+			},
+		},
+		"PodInitContainerFailed": {
+			yaml: podInitContainerFailedManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonFailed,
+				Message: "Containers failed: migrate (Error: exit code 1)",
+			},
+		},
+		"DeploymentInProgress": {
+			yaml: deploymentInProgressManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: "Available: 0/1",
+			},
+		},
+		"DeploymentPodFailed": {
+			yaml: deploymentInProgressManifest,
+			opts: []Option{WithLookup(lookup)},
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonFailed,
+				Message: "Pod web-7d4b9c has failed containers: web (ImagePullBackOff)",
+			},
+		},
+		"PVCWaitForFirstConsumer": {
+			yaml: pvcWaitForFirstConsumerManifest,
+			opts: []Option{WithLookup(lookup)},
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "PVC is waiting for first consumer. storageClass: local-path",
+			},
+		},
+		"WebhookConfigurationEndpoints": {
+			yaml: webhookConfigurationManifest,
+			opts: []Option{WithLookup(lookup)},
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "ValidatingWebhookConfiguration is configured. webhooks: 2",
+			},
+		},
+		"WebhookConfigurationNoEndpoints": {
+			yaml: webhookConfigurationNoEndpointsManifest,
+			opts: []Option{WithLookup(lookup)},
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: "webhook validate.policy.example.com service policy-system/other-webhook has no ready endpoints",
+			},
+		},
+		"ServiceLoadBalancerReady": {
+			yaml: serviceLoadBalancerReadyManifest,
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "LoadBalancer service ready. address: 172.18.0.20",
+			},
+		},
+		"ServiceLoadBalancerPending": {
+			yaml: serviceLoadBalancerPendingManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: "LoadBalancer ingress not set. Service type: LoadBalancer",
+			},
+		},
+		"ServiceLoadBalancerPendingAccepted": {
+			yaml: serviceLoadBalancerPendingManifest,
+			opts: []Option{WithAcceptPendingLoadBalancers(true)},
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "LoadBalancer ingress pending, accepted. Service type: LoadBalancer",
+			},
+		},
+		"ServiceExternalName": {
+			yaml: serviceExternalNameManifest,
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "ExternalName service ready. externalName: db.example.com",
+			},
+		},
+		"ServiceHeadless": {
+			yaml: serviceHeadlessManifest,
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "headless service ready",
+			},
+		},
+		"IngressReady": {
+			yaml: ingressReadyManifest,
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "Ingress is ready. address: 172.18.0.10,lb.example.com",
+			},
+		},
+		"IngressPending": {
+			yaml: ingressPendingManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: "Ingress has no load balancer address",
+			},
+		},
+		"NamespaceActive": {
+			yaml: namespaceActiveManifest,
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "Namespace is active",
+			},
+		},
+		"NamespaceTerminating": {
+			yaml: namespaceTerminatingManifest,
+			result: &Result{
+				Status: metav1.ConditionFalse,
+				Reason: ReasonTerminating,
+			},
+		},
+		"PVBound": {
+			yaml: pvBoundManifest,
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "PV is Bound",
+			},
+		},
+		"PVFailed": {
+			yaml: pvFailedManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonFailed,
+				Message: "recycler failed to delete the volume",
+			},
+		},
+		"PVCProvisioning": {
+			yaml: pvcProvisioningManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: "PVC is not Bound, waiting for provisioner rancher.io/local-path. phase: Pending",
+			},
+		},
+		"PVCLost": {
+			yaml: pvcLostManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonFailed,
+				Message: "PVC lost its underlying PersistentVolume",
+			},
+		},
+		"APIServiceAvailable": {
+			yaml: apiServiceAvailableManifest,
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "APIService is available",
+			},
+		},
+		"APIServiceUnavailable": {
+			yaml: apiServiceUnavailableManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: `APIService is not available. reason: MissingEndpoints, message: endpoints for service/metrics-server in "kube-system" have no addresses`,
+			},
+		},
+		"HPAReady": {
+			yaml: hpaReadyManifest,
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "HPA is able to scale and scaling is active",
+			},
+		},
+		"HPAMissingMetrics": {
+			yaml: hpaMissingMetricsManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: "HPA scaling is not active. reason: FailedGetResourceMetric, message: unable to get metrics for resource cpu",
+			},
+		},
+		"HPAV1Ready": {
+			yaml: hpaV1ReadyManifest,
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "HPA is able to scale and scaling is active",
+			},
+		},
+		"WebhookConfiguration": {
+			yaml: webhookConfigurationManifest,
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "ValidatingWebhookConfiguration is configured. webhooks: 2",
+			},
+		},
+		"WebhookConfigurationInvalid": {
+			yaml: webhookConfigurationInvalidManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonFailed,
+				Message: "webhook mutate.policy.example.com has no url or service in its clientConfig",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+
+			u := testutil.YamlToUnstructured(t, tc.yaml)
+
+			res, err := Compute(u, tc.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, *tc.result, *res)
+		})
+	}
+}
+
+func TestWithPodFailuresCurrentRevision(t *testing.T) {
+	cases := map[string]struct {
+		yaml    string
+		objects []string
+		result  *Result
+	}{
+		"DeploymentOldRevisionPodFailed": {
+			yaml: deploymentInProgressManifest,
+			objects: []string{
+				replicaSetOldRevisionManifest,
+				replicaSetNewRevisionManifest,
+				podOldRevisionFailedManifest,
+				podNewRevisionRunningManifest,
+			},
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: "Available: 0/1",
+			},
+		},
+		"DeploymentUnknownRevision": {
+			yaml: deploymentInProgressManifest,
+			objects: []string{
+				podOldRevisionFailedManifest,
+			},
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: "Available: 0/1",
+			},
+		},
+		"StatefulSetOldRevisionPodFailed": {
+			yaml: statefulSetInProgressManifest,
+			objects: []string{
+				podStatefulSetOldRevisionFailedManifest,
+			},
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: "Ready: 1/2",
+			},
+		},
+		"StatefulSetUpdateRevisionPodFailed": {
+			yaml: statefulSetInProgressManifest,
+			objects: []string{
+				podStatefulSetNewRevisionFailedManifest,
+			},
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonFailed,
+				Message: "Pod db-1 has failed containers: db (ErrImagePull)",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u := testutil.YamlToUnstructured(t, tc.yaml)
+			res, err := Compute(u, WithLookup(testutil.NewFakeLookup(t, tc.objects...)))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.result, res)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package status
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Check is an evaluated check explaining the status of a resource.
+type Check struct {
+	// Name of the check, e.g. the status field or condition type
+	Name string `json:"name" yaml:"name"`
+	// Passed indicates the observed value meets the expected value
+	Passed bool `json:"passed" yaml:"passed"`
+	// Observed value
+	Observed string `json:"observed,omitempty" yaml:"observed,omitempty"`
+	// Expected value
+	Expected string `json:"expected,omitempty" yaml:"expected,omitempty"`
+	// Message of the condition or the expression of a rule
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// LastTransitionTime of the condition
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty" yaml:"lastTransitionTime,omitempty"`
+}
+
+// FailedChecks returns the checks of the result that did not pass.
+func (r *Result) FailedChecks() []Check {
+	var checks []Check
+	for _, c := range r.Checks {
+		if !c.Passed {
+			checks = append(checks, c)
+		}
+	}
+	return checks
+}
+
+// String returns a human readable representation of the check.
+func (r Check) String() string {
+	s := fmt.Sprintf("%s: observed %q, expected %q", r.Name, r.Observed, r.Expected)
+	if r.Message != "" {
+		s = fmt.Sprintf("%s, message: %s", s, r.Message)
+	}
+	if r.LastTransitionTime != nil {
+		s = fmt.Sprintf("%s, since: %s", s, r.LastTransitionTime.UTC().Format("2006-01-02T15:04:05Z"))
+	}
+	return s
+}
+
+// check records a check evaluated by a rule to compute the status, the checks
+// are returned in the Result with the Explain option. It returns passed, such
+// that the rules can record and evaluate a check in one statement.
+func (o *Options) check(name string, passed bool, observed, expected any) bool {
+	o.record(Check{
+		Name:     name,
+		Passed:   passed,
+		Observed: fmt.Sprint(observed),
+		Expected: fmt.Sprint(expected),
+	})
+	return passed
+}
+
+// checkCondition records the check of a condition with the expected status, the
+// rule decides whether the condition passed as e.g. the reason of the condition
+// is relevant as well. It returns passed.
+func (o *Options) checkCondition(c Condition, passed bool, expected metav1.ConditionStatus) bool {
+	check := Check{
+		Name:     fmt.Sprintf("condition %s", c.Type),
+		Passed:   passed,
+		Observed: string(c.Status),
+		Expected: string(expected),
+		Message:  c.Message,
+	}
+	if !c.LastTransitionTime.IsZero() {
+		check.LastTransitionTime = c.LastTransitionTime.DeepCopy()
+	}
+	o.record(check)
+	return passed
+}
+
+// record appends the check, a check evaluated before with the same outcome is
+// recorded once, e.g. the Ready condition refined by a built-in rule.
+func (o *Options) record(check Check) {
+	for _, c := range o.checks {
+		if c.Name == check.Name && c.Passed == check.Passed && c.Observed == check.Observed && c.Expected == check.Expected {
+			return
+		}
+	}
+	o.checks = append(o.checks, check)
+}
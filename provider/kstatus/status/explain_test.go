@@ -0,0 +1,80 @@
+package status
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kform-providers/kubernetes/provider/kstatus/status/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var deploymentRollingOutManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+  generation: 2
+spec:
+  replicas: 2
+status:
+  observedGeneration: 2
+  replicas: 2
+  updatedReplicas: 1
+  availableReplicas: 2
+  readyReplicas: 2
+  conditions:
+  - type: Progressing
+    status: "True"
+    reason: ReplicaSetUpdated
+    lastTransitionTime: "2024-03-29T00:58:41Z"
+  - type: Available
+    status: "True"
+    reason: MinimumReplicasAvailable
+    lastTransitionTime: "2024-03-29T00:50:00Z"
+`
+
+func TestComputeExplain(t *testing.T) {
+	u := testutil.YamlToUnstructured(t, deploymentRollingOutManifest)
+
+	res, err := Compute(u)
+	assert.NoError(t, err)
+	assert.Empty(t, res.Checks)
+
+	res, err = Compute(u, WithExplain(true))
+	assert.NoError(t, err)
+	assert.Equal(t, ReasonInProgress, res.Reason)
+	assert.Equal(t, "Updated: 1/2", res.Message)
+
+	// the rule stops at the first failed check, the conditions are not evaluated
+	assert.Equal(t, []Check{
+		{Name: "observedGeneration", Passed: true, Observed: "2", Expected: "2"},
+		{Name: "replicas", Passed: true, Observed: "2", Expected: "2"},
+		{Name: "updatedReplicas", Passed: false, Observed: "1", Expected: "2"},
+	}, res.Checks)
+	assert.Equal(t, []Check{
+		{Name: "updatedReplicas", Passed: false, Observed: "1", Expected: "2"},
+	}, res.FailedChecks())
+}
+
+func TestComputeExplainConditions(t *testing.T) {
+	u := testutil.YamlToUnstructured(t, strings.Replace(deploymentRollingOutManifest, "updatedReplicas: 1", "updatedReplicas: 2", 1))
+
+	res, err := Compute(u, WithExplain(true))
+	assert.NoError(t, err)
+	assert.Equal(t, ReasonReady, res.Reason)
+
+	available := metav1.NewTime(time.Date(2024, 3, 29, 0, 50, 0, 0, time.UTC).Local())
+	assert.Equal(t, []Check{
+		{Name: "observedGeneration", Passed: true, Observed: "2", Expected: "2"},
+		{Name: "replicas", Passed: true, Observed: "2", Expected: "2"},
+		{Name: "updatedReplicas", Passed: true, Observed: "2", Expected: "2"},
+		{Name: "pendingTermination", Passed: true, Observed: "0", Expected: "0"},
+		{Name: "availableReplicas", Passed: true, Observed: "2", Expected: "2"},
+		{Name: "readyReplicas", Passed: true, Observed: "2", Expected: "2"},
+		{Name: "condition Available", Passed: true, Observed: "True", Expected: "True", LastTransitionTime: &available},
+	}, res.Checks)
+	assert.Empty(t, res.FailedChecks())
+}
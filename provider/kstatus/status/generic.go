@@ -7,7 +7,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
-func checkGenericProperties(u *unstructured.Unstructured) (*Result, error) {
+func checkGenericProperties(u *unstructured.Unstructured, o *Options) (*Result, error) {
 	obj := u.UnstructuredContent()
 
 	// Check if the resource is scheduled for deletion
@@ -16,31 +16,73 @@ func checkGenericProperties(u *unstructured.Unstructured) (*Result, error) {
 		return nil, fmt.Errorf("cannot lookup metadata.deletionTimestamp from resource: %w", err)
 	}
 	if found && deletionTimestamp != "" {
+		o.check("deletionTimestamp", false, deletionTimestamp, "")
 		return terminating(), nil
 	}
-	res, err := checkGeneration(u)
-	if res != nil || err != nil {
-		return res, err
-	}
-	// Check if the resource has any of the standard conditions. If so, we just use them
-	// and no need to look at anything else.
-	objc, err := GetObjectWithConditions(obj)
+	return checkGeneration(u, o)
+}
+
+// checkGenericConditions checks if the resource has any of the standard conditions.
+// If so, we just use them and no need to look at anything else.
+func checkGenericConditions(u *unstructured.Unstructured, o *Options) (*Result, error) {
+	objc, err := GetObjectWithConditions(u.UnstructuredContent())
 	if err != nil {
 		return nil, err
 	}
-	for _, cond := range objc.Status.Conditions {
-		if cond.Type == string(ConditionTypeReady) {
-			if cond.Status == metav1.ConditionTrue {
-				return ready(cond.Message), nil
-			} else {
-				return inProgress(cond.Message), nil
-			}
+	// Stalled and Reconciling are abnormal-true conditions, they take precedence
+	// over the Ready condition as the Ready condition might not yet be updated.
+	if c, found := getCondition(objc.Status.Conditions, string(ConditionTypeStalled)); found {
+		if !o.checkCondition(c, c.Status != metav1.ConditionTrue, metav1.ConditionFalse) {
+			return failed(c.Message), nil
+		}
+	}
+	if c, found := getCondition(objc.Status.Conditions, string(ConditionTypeReconciling)); found {
+		if !o.checkCondition(c, c.Status != metav1.ConditionTrue, metav1.ConditionFalse) {
+			return inProgress(c.Message), nil
+		}
+	}
+	if c, found := getCondition(objc.Status.Conditions, string(ConditionTypeReady)); found {
+		if o.checkCondition(c, c.Status == metav1.ConditionTrue, metav1.ConditionTrue) {
+			return ready(c.Message), nil
 		}
+		return inProgress(c.Message), nil
 	}
 	return nil, nil
 }
 
-func checkGeneration(u *unstructured.Unstructured) (*Result, error) {
+// checkGenericPhase computes the status from the commonly used status.phase field.
+// It returns nil when the resource has no phase or a phase it does not recognise,
+// e.g. Active, Bound or Available.
+func checkGenericPhase(u *unstructured.Unstructured, o *Options) *Result {
+	phase := GetStringField(u.UnstructuredContent(), ".status.phase", "")
+	msg := GetStringField(u.UnstructuredContent(), ".status.message", "")
+	switch phase {
+	case "":
+		return nil
+	case "Failed", "Error":
+		o.check("phase", false, phase, "Ready, Running or Succeeded")
+		if msg == "" {
+			msg = fmt.Sprintf("%s phase: %s", u.GetKind(), phase)
+		}
+		return failed(msg)
+	case "Ready", "Running", "Succeeded":
+		o.check("phase", true, phase, "Ready, Running or Succeeded")
+		if msg == "" {
+			msg = fmt.Sprintf("%s phase: %s", u.GetKind(), phase)
+		}
+		return ready(msg)
+	case "Pending":
+		o.check("phase", false, phase, "Ready, Running or Succeeded")
+		if msg == "" {
+			msg = fmt.Sprintf("%s phase: %s", u.GetKind(), phase)
+		}
+		return inProgress(msg)
+	default:
+		return nil
+	}
+}
+
+func checkGeneration(u *unstructured.Unstructured, o *Options) (*Result, error) {
 	// ensure that the meta generation is observed
 	generation, found, err := unstructured.NestedInt64(u.Object, "metadata", "generation")
 	if err != nil {
@@ -54,7 +96,7 @@ func checkGeneration(u *unstructured.Unstructured) (*Result, error) {
 		return nil, fmt.Errorf("cannot lookup status.observedGeneration from resource: %w", err)
 	}
 	if found {
-		if observedGeneration != generation {
+		if !o.check("observedGeneration", observedGeneration == generation, observedGeneration, generation) {
 			msg := fmt.Sprintf("%s generation is %d, but latest observed generation is %d", u.GetKind(), generation, observedGeneration)
 			return inProgress(msg), nil
 		}
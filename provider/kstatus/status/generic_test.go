@@ -0,0 +1,263 @@
+package status
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kform-providers/kubernetes/provider/kstatus/status/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var reconcilingManifest = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: test
+  generation: 2
+status:
+  observedGeneration: 2
+  conditions:
+  - type: Reconciling
+    status: "True"
+    reason: Progressing
+    message: waiting for dependencies
+  - type: Ready
+    status: "True"
+`
+
+var stalledManifest = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: test
+  generation: 2
+status:
+  observedGeneration: 2
+  conditions:
+  - type: Reconciling
+    status: "True"
+    reason: Progressing
+  - type: Stalled
+    status: "True"
+    reason: InvalidSpec
+    message: spec.size is invalid
+`
+
+var reconciledManifest = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: test
+  generation: 2
+status:
+  observedGeneration: 2
+  conditions:
+  - type: Reconciling
+    status: "False"
+  - type: Stalled
+    status: "False"
+  - type: Ready
+    status: "True"
+    message: widget ready
+`
+
+var generationNotObservedManifest = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: test
+  generation: 2
+status:
+  observedGeneration: 1
+  conditions:
+  - type: Ready
+    status: "True"
+`
+
+var terminatingManifest = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: test
+  deletionTimestamp: "2024-03-29T00:58:41Z"
+status:
+  conditions:
+  - type: Ready
+    status: "True"
+`
+
+var phaseManifest = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: test
+status:
+  phase: %s
+`
+
+var phaseWithMessageManifest = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: test
+status:
+  phase: Error
+  message: cannot connect to backend
+`
+
+var phaseWithConditionsManifest = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: test
+status:
+  phase: Active
+  conditions:
+  - type: Ready
+    status: "False"
+    message: waiting for the backend
+`
+
+var podUnknownPhaseManifest = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test
+status:
+  phase: Unknown
+`
+
+func TestComputeGeneric(t *testing.T) {
+	cases := map[string]struct {
+		yaml   string
+		result *Result
+	}{
+		"Reconciling": {
+			yaml: reconcilingManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: "waiting for dependencies",
+			},
+		},
+		"Stalled": {
+			yaml: stalledManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonFailed,
+				Message: "spec.size is invalid",
+			},
+		},
+		"Reconciled": {
+			yaml: reconciledManifest,
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "widget ready",
+			},
+		},
+		"GenerationNotObserved": {
+			yaml: generationNotObservedManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: "Widget generation is 2, but latest observed generation is 1",
+			},
+		},
+		"Terminating": {
+			yaml: terminatingManifest,
+			result: &Result{
+				Status: metav1.ConditionFalse,
+				Reason: ReasonTerminating,
+			},
+		},
+		"PhaseFailed": {
+			yaml: fmt.Sprintf(phaseManifest, "Failed"),
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonFailed,
+				Message: "Widget phase: Failed",
+			},
+		},
+		"PhaseError": {
+			yaml: phaseWithMessageManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonFailed,
+				Message: "cannot connect to backend",
+			},
+		},
+		"PhaseReady": {
+			yaml: fmt.Sprintf(phaseManifest, "Ready"),
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "Widget phase: Ready",
+			},
+		},
+		"PhaseRunning": {
+			yaml: fmt.Sprintf(phaseManifest, "Running"),
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "Widget phase: Running",
+			},
+		},
+		"PhaseSucceeded": {
+			yaml: fmt.Sprintf(phaseManifest, "Succeeded"),
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "Widget phase: Succeeded",
+			},
+		},
+		"PhasePending": {
+			yaml: fmt.Sprintf(phaseManifest, "Pending"),
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: "Widget phase: Pending",
+			},
+		},
+		// the phases that are not recognised do not determine the status
+		"PhaseActive": {
+			yaml:   fmt.Sprintf(phaseManifest, "Active"),
+			result: noStatusInfo(),
+		},
+		"PhaseBound": {
+			yaml:   fmt.Sprintf(phaseManifest, "Bound"),
+			result: noStatusInfo(),
+		},
+		"PhaseCompleted": {
+			yaml:   fmt.Sprintf(phaseManifest, "Completed"),
+			result: noStatusInfo(),
+		},
+		"PhaseActiveWithConditions": {
+			yaml: phaseWithConditionsManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: "waiting for the backend",
+			},
+		},
+		"PodPhaseUnknown": {
+			yaml: podUnknownPhaseManifest,
+			result: &Result{
+				Status:  metav1.ConditionUnknown,
+				Reason:  ReasonUnknown,
+				Message: "Pod phase: Unknown",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+
+			u := testutil.YamlToUnstructured(t, tc.yaml)
+
+			res, err := Compute(u)
+			assert.NoError(t, err)
+			assert.Equal(t, *tc.result, *res)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package status
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kform-providers/kubernetes/provider/kstatus/status/testutil"
+	"github.com/stretchr/testify/assert"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/yaml"
+)
+
+var update = flag.Bool("update", false, "update the expected results of the golden fixtures")
+
+// goldenTime is the time at which the golden fixtures are evaluated.
+var goldenTime = time.Date(2024, 3, 29, 1, 0, 0, 0, time.UTC)
+
+const expectedSuffix = ".expected.yaml"
+
+// TestGolden computes the status of the objects in testdata/<kind>/<case>.yaml and
+// compares the result with testdata/<kind>/<case>.expected.yaml. Run the test with
+// -update to regenerate the expected results:
+//
+//	go test ./provider/kstatus/status -run TestGolden -update
+func TestGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*", "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, expectedSuffix) {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(file, "testdata"+string(filepath.Separator)), ".yaml")
+		t.Run(name, func(t *testing.T) {
+			b, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			u := testutil.YamlToUnstructured(t, string(b))
+
+			res, err := Compute(u, WithExplain(true), WithClock(clocktesting.NewFakePassiveClock(goldenTime)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the checks explain the result, a ready result cannot have failed checks
+			if res.Reason == ReasonReady {
+				assert.Empty(t, res.FailedChecks())
+			}
+			got, err := yaml.Marshal(res)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expectedFile := strings.TrimSuffix(file, ".yaml") + expectedSuffix
+			if *update {
+				if err := os.WriteFile(expectedFile, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := os.ReadFile(expectedFile)
+			if err != nil {
+				t.Fatalf("cannot read expected result, run with -update to create it: %v", err)
+			}
+			assert.Equal(t, string(expected), string(got))
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package status
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// History tracks per object how long it has been in progress without any
+// change in its status, it reports an object as failed when it made no progress
+// within the deadline of its GroupKind. A History can be shared across calls
+// to Compute and is safe for concurrent use.
+type History struct {
+	m         sync.Mutex
+	deadlines map[schema.GroupKind]time.Duration
+	entries   map[types.UID]historyEntry
+}
+
+type historyEntry struct {
+	message string
+	since   time.Time
+}
+
+// NewHistory returns a History with the progress deadlines per GroupKind. Objects
+// of a GroupKind without deadline are never reported as failed.
+func NewHistory(deadlines map[schema.GroupKind]time.Duration) *History {
+	return &History{
+		deadlines: deadlines,
+		entries:   map[types.UID]historyEntry{},
+	}
+}
+
+// observe records the result of the object and returns a failed result when the
+// object made no progress within the deadline of its GroupKind.
+func (r *History) observe(u *unstructured.Unstructured, res *Result, now time.Time) *Result {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	uid := u.GetUID()
+	if uid == "" {
+		return res
+	}
+	if res.Reason != ReasonInProgress {
+		delete(r.entries, uid)
+		return res
+	}
+	deadline, ok := r.deadlines[u.GroupVersionKind().GroupKind()]
+	if !ok {
+		return res
+	}
+	// the message of an in progress result reflects the progress, e.g. Ready: 1/3
+	entry, ok := r.entries[uid]
+	if !ok || entry.message != res.Message {
+		r.entries[uid] = historyEntry{message: res.Message, since: now}
+		return res
+	}
+	if stuck := now.Sub(entry.since); stuck >= deadline {
+		msg := fmt.Sprintf("no progress for %s: %s", stuck.Round(time.Second), res.Message)
+		f := failed(msg)
+		f.Checks = append(res.Checks, Check{
+			Name:     "progress",
+			Passed:   false,
+			Observed: fmt.Sprintf("no progress for %s", stuck.Round(time.Second)),
+			Expected: fmt.Sprintf("progress within %s", deadline),
+		})
+		return f
+	}
+	return res
+}
@@ -0,0 +1,86 @@
+package status
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kform-providers/kubernetes/provider/kstatus/status/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+var deploymentStuckManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+  uid: 5a0ad4b5-1f5e-4c4c-9a50-6ad3c9d2a1f4
+  generation: 1
+spec:
+  replicas: 3
+status:
+  observedGeneration: 1
+  replicas: 3
+  updatedReplicas: %d
+`
+
+var podUnschedulableManifest = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  namespace: default
+  creationTimestamp: "2024-03-29T00:58:41Z"
+status:
+  phase: Pending
+  conditions:
+  - type: PodScheduled
+    status: "False"
+    reason: Unschedulable
+`
+
+func TestHistory(t *testing.T) {
+	clock := clocktesting.NewFakePassiveClock(time.Date(2024, 3, 29, 1, 0, 0, 0, time.UTC))
+	history := NewHistory(map[schema.GroupKind]time.Duration{
+		{Group: "apps", Kind: "Deployment"}: 5 * time.Minute,
+	})
+	compute := func(updated int) *Result {
+		u := testutil.YamlToUnstructured(t, fmt.Sprintf(deploymentStuckManifest, updated))
+		res, err := Compute(u, WithClock(clock), WithHistory(history))
+		assert.NoError(t, err)
+		return res
+	}
+
+	assert.Equal(t, ReasonInProgress, compute(1).Reason)
+	clock.SetTime(clock.Now().Add(4 * time.Minute))
+	assert.Equal(t, ReasonInProgress, compute(1).Reason)
+
+	// progress resets the deadline
+	clock.SetTime(clock.Now().Add(2 * time.Minute))
+	assert.Equal(t, ReasonInProgress, compute(2).Reason)
+	clock.SetTime(clock.Now().Add(4 * time.Minute))
+	assert.Equal(t, ReasonInProgress, compute(2).Reason)
+
+	clock.SetTime(clock.Now().Add(time.Minute))
+	res := compute(2)
+	assert.Equal(t, ReasonFailed, res.Reason)
+	assert.Equal(t, "no progress for 5m0s: Updated: 2/3", res.Message)
+}
+
+func TestComputeClock(t *testing.T) {
+	u := testutil.YamlToUnstructured(t, podUnschedulableManifest)
+
+	clock := clocktesting.NewFakePassiveClock(time.Date(2024, 3, 29, 0, 58, 50, 0, time.UTC))
+	res, err := Compute(u, WithClock(clock))
+	assert.NoError(t, err)
+	assert.Equal(t, ReasonInProgress, res.Reason)
+
+	clock.SetTime(clock.Now().Add(ScheduleWindow))
+	res, err = Compute(u, WithClock(clock))
+	assert.NoError(t, err)
+	assert.Equal(t, ReasonFailed, res.Reason)
+	assert.Equal(t, "Pod could not be scheduled", res.Message)
+}
@@ -0,0 +1,92 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DefaultMaxLookups is the default budget of lookups a single call to Compute
+// can perform.
+const DefaultMaxLookups = 20
+
+// ErrLookupBudgetExceeded is returned by the lookup when a call to Compute
+// performed more lookups than its budget allows.
+var ErrLookupBudgetExceeded = errors.New("lookup budget exceeded")
+
+var (
+	podGVK           = schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	replicaSetGVK    = schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}
+	storageClassGVK  = schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "StorageClass"}
+	endpointSliceGVK = schema.GroupVersionKind{Group: "discovery.k8s.io", Version: "v1", Kind: "EndpointSlice"}
+)
+
+// Lookup provides read-only access to the objects related to the resource for
+// which the status is computed, e.g. the pods of a workload.
+type Lookup interface {
+	// Get returns the object of the kind with the namespace and name.
+	Get(gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error)
+	// List returns the objects of the kind in the namespace matching the selector.
+	List(gvk schema.GroupVersionKind, namespace string, selector labels.Selector) ([]unstructured.Unstructured, error)
+}
+
+// cachedLookup caches the results of a Lookup for the duration of a call to
+// Compute and limits the number of lookups to the budget.
+type cachedLookup struct {
+	lookup Lookup
+	budget int
+	gets   map[string]*unstructured.Unstructured
+	lists  map[string][]unstructured.Unstructured
+}
+
+func newCachedLookup(lookup Lookup, budget int) *cachedLookup {
+	return &cachedLookup{
+		lookup: lookup,
+		budget: budget,
+		gets:   map[string]*unstructured.Unstructured{},
+		lists:  map[string][]unstructured.Unstructured{},
+	}
+}
+
+func (r *cachedLookup) spend() error {
+	if r.budget <= 0 {
+		return ErrLookupBudgetExceeded
+	}
+	r.budget--
+	return nil
+}
+
+func (r *cachedLookup) Get(gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	key := fmt.Sprintf("%s/%s/%s", gvk.String(), namespace, name)
+	if u, ok := r.gets[key]; ok {
+		return u, nil
+	}
+	if err := r.spend(); err != nil {
+		return nil, err
+	}
+	u, err := r.lookup.Get(gvk, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	r.gets[key] = u
+	return u, nil
+}
+
+func (r *cachedLookup) List(gvk schema.GroupVersionKind, namespace string, selector labels.Selector) ([]unstructured.Unstructured, error) {
+	key := fmt.Sprintf("%s/%s/%s", gvk.String(), namespace, selector.String())
+	if ul, ok := r.lists[key]; ok {
+		return ul, nil
+	}
+	if err := r.spend(); err != nil {
+		return nil, err
+	}
+	ul, err := r.lookup.List(gvk, namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+	r.lists[key] = ul
+	return ul, nil
+}
@@ -0,0 +1,28 @@
+package status
+
+import (
+	"testing"
+
+	"github.com/kform-providers/kubernetes/provider/kstatus/status/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestCachedLookup(t *testing.T) {
+	fake := testutil.NewFakeLookup(t, podImagePullBackOffManifest)
+	lookup := newCachedLookup(fake, 2)
+
+	selector := labels.SelectorFromSet(labels.Set{"app": "web"})
+	for i := 0; i < 3; i++ {
+		pods, err := lookup.List(podGVK, "default", selector)
+		assert.NoError(t, err)
+		assert.Len(t, pods, 1)
+	}
+	assert.Equal(t, 1, fake.Calls)
+
+	_, err := lookup.Get(podGVK, "default", "web-7d4b9c")
+	assert.NoError(t, err)
+	_, err = lookup.Get(podGVK, "default", "web")
+	assert.ErrorIs(t, err, ErrLookupBudgetExceeded)
+	assert.Equal(t, 2, fake.Calls)
+}
@@ -0,0 +1,159 @@
+package status
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// objectVariable is the name of the variable through which the object is
+// made available to the CEL expressions of a Rule.
+const objectVariable = "object"
+
+// ruleCostLimit limits the cost of evaluating a single CEL expression
+const ruleCostLimit = 1000000
+
+// A Rule defines CEL expressions that compute the status of resources of a
+// GroupKind. The expressions are evaluated against the object, which is
+// available as the `object` variable, e.g.
+//
+//	object.status.conditions.exists(c, c.type == 'Healthy' && c.status == 'True')
+type Rule struct {
+	// Ready is a CEL expression returning a bool that indicates the resource is ready.
+	Ready string `json:"ready" yaml:"ready"`
+	// Failed is an optional CEL expression returning a bool that indicates the
+	// resource failed. It is evaluated before the Ready expression.
+	Failed string `json:"failed,omitempty" yaml:"failed,omitempty"`
+	// Message is an optional CEL expression returning a string that describes the
+	// status of the resource.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// RuleRegistry holds the compiled rules per GroupKind.
+type RuleRegistry struct {
+	m     sync.RWMutex
+	env   *cel.Env
+	rules map[schema.GroupKind]*compiledRule
+}
+
+// DefaultRuleRegistry is the registry consulted by Compute when no registry is
+// set with WithRuleRegistry.
+var DefaultRuleRegistry = NewRuleRegistry()
+
+// RegisterRule registers the rule for the GroupKind in the DefaultRuleRegistry.
+func RegisterRule(gk schema.GroupKind, rule Rule) error {
+	return DefaultRuleRegistry.Register(gk, rule)
+}
+
+// NewRuleRegistry returns an empty RuleRegistry.
+func NewRuleRegistry() *RuleRegistry {
+	env, err := cel.NewEnv(cel.Variable(objectVariable, cel.DynType))
+	if err != nil {
+		// the environment is static, so this can only happen due to a programming error
+		panic(fmt.Sprintf("cannot create cel environment: %v", err))
+	}
+	return &RuleRegistry{
+		env:   env,
+		rules: map[schema.GroupKind]*compiledRule{},
+	}
+}
+
+// Register compiles the rule and registers it for the GroupKind, replacing any
+// rule that was registered before for the same GroupKind.
+func (r *RuleRegistry) Register(gk schema.GroupKind, rule Rule) error {
+	if rule.Ready == "" {
+		return fmt.Errorf("rule for %s: a ready expression is required", gk.String())
+	}
+	cr := &compiledRule{}
+	var err error
+	if cr.ready, err = r.compile(rule.Ready, cel.BoolType); err != nil {
+		return fmt.Errorf("rule for %s: ready: %w", gk.String(), err)
+	}
+	if rule.Failed != "" {
+		if cr.failed, err = r.compile(rule.Failed, cel.BoolType); err != nil {
+			return fmt.Errorf("rule for %s: failed: %w", gk.String(), err)
+		}
+	}
+	if rule.Message != "" {
+		if cr.message, err = r.compile(rule.Message, cel.StringType); err != nil {
+			return fmt.Errorf("rule for %s: message: %w", gk.String(), err)
+		}
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.rules[gk] = cr
+	return nil
+}
+
+// Unregister removes the rule of the GroupKind from the registry.
+func (r *RuleRegistry) Unregister(gk schema.GroupKind) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	delete(r.rules, gk)
+}
+
+func (r *RuleRegistry) get(gk schema.GroupKind) *compiledRule {
+	r.m.RLock()
+	defer r.m.RUnlock()
+	return r.rules[gk]
+}
+
+func (r *RuleRegistry) compile(expr string, outputType *cel.Type) (cel.Program, error) {
+	ast, iss := r.env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if ast.OutputType() != outputType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expected expression to return %s, got %s", outputType, ast.OutputType())
+	}
+	return r.env.Program(ast, cel.CostLimit(ruleCostLimit))
+}
+
+type compiledRule struct {
+	ready   cel.Program
+	failed  cel.Program
+	message cel.Program
+}
+
+// compute returns the status of the object. Errors evaluating the expressions,
+// e.g. due to status fields that are not yet populated, are reported as in progress.
+func (r *compiledRule) compute(u *unstructured.Unstructured, o *Options) (*Result, error) {
+	vars := map[string]interface{}{objectVariable: u.UnstructuredContent()}
+
+	msg := ""
+	if r.message != nil {
+		out, _, err := r.message.Eval(vars)
+		if err == nil {
+			if s, ok := out.Value().(string); ok {
+				msg = s
+			}
+		}
+	}
+
+	if r.failed != nil {
+		out, _, err := r.failed.Eval(vars)
+		if err == nil {
+			if failedResult, ok := out.Value().(bool); ok && !o.check("failed rule", !failedResult, failedResult, false) {
+				return failed(msg), nil
+			}
+		}
+	}
+
+	out, _, err := r.ready.Eval(vars)
+	if err != nil {
+		o.check("ready rule", false, err.Error(), true)
+		return inProgress(fmt.Sprintf("cannot evaluate ready rule: %s", err.Error())), nil
+	}
+	readyResult, ok := out.Value().(bool)
+	if !ok {
+		return nil, fmt.Errorf("ready rule for %s returned %T, expected bool", u.GroupVersionKind().GroupKind().String(), out.Value())
+	}
+	if o.check("ready rule", readyResult, readyResult, true) {
+		return ready(msg), nil
+	}
+	return inProgress(msg), nil
+}
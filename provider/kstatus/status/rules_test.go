@@ -0,0 +1,182 @@
+package status
+
+import (
+	"testing"
+
+	"github.com/kform-providers/kubernetes/provider/kstatus/status/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var argoApplicationHealthyManifest = `
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: guestbook
+  namespace: argocd
+status:
+  health:
+    status: Healthy
+  sync:
+    status: Synced
+`
+
+var argoApplicationDegradedManifest = `
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: guestbook
+  namespace: argocd
+status:
+  health:
+    status: Degraded
+    message: back-off restarting failed container
+  sync:
+    status: Synced
+`
+
+var argoApplicationNoStatusManifest = `
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: guestbook
+  namespace: argocd
+`
+
+var bucketReadyManifest = `
+apiVersion: s3.aws.upbound.io/v1beta1
+kind: Bucket
+metadata:
+  name: bucket
+  generation: 1
+status:
+  conditions:
+  - type: Synced
+    status: "True"
+    reason: ReconcileSuccess
+  - type: Ready
+    status: "False"
+    reason: Creating
+`
+
+func TestComputeRules(t *testing.T) {
+	argoApplication := schema.GroupKind{Group: "argoproj.io", Kind: "Application"}
+	bucket := schema.GroupKind{Group: "s3.aws.upbound.io", Kind: "Bucket"}
+
+	assert.NoError(t, RegisterRule(argoApplication, Rule{
+		Ready:   "object.status.health.status == 'Healthy'",
+		Failed:  "object.status.health.status == 'Degraded'",
+		Message: "has(object.status.health.message) ? object.status.health.message : object.status.health.status",
+	}))
+	t.Cleanup(func() { DefaultRuleRegistry.Unregister(argoApplication) })
+	assert.NoError(t, RegisterRule(bucket, Rule{
+		Ready: "object.status.conditions.exists(c, c.type == 'Synced' && c.status == 'True')",
+	}))
+	t.Cleanup(func() { DefaultRuleRegistry.Unregister(bucket) })
+
+	cases := map[string]struct {
+		yaml   string
+		result *Result
+	}{
+		"ArgoApplicationHealthy": {
+			yaml: argoApplicationHealthyManifest,
+			result: &Result{
+				Status:  metav1.ConditionTrue,
+				Reason:  ReasonReady,
+				Message: "Healthy",
+			},
+		},
+		"ArgoApplicationDegraded": {
+			yaml: argoApplicationDegradedManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonFailed,
+				Message: "back-off restarting failed container",
+			},
+		},
+		"ArgoApplicationNoStatus": {
+			yaml: argoApplicationNoStatusManifest,
+			result: &Result{
+				Status:  metav1.ConditionFalse,
+				Reason:  ReasonInProgress,
+				Message: "cannot evaluate ready rule: no such key: status",
+			},
+		},
+		// the rule takes precedence over the generic Ready condition
+		"BucketRuleOverridesReadyCondition": {
+			yaml: bucketReadyManifest,
+			result: &Result{
+				Status: metav1.ConditionTrue,
+				Reason: ReasonReady,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+
+			u := testutil.YamlToUnstructured(t, tc.yaml)
+
+			res, err := Compute(u)
+			assert.NoError(t, err)
+			assert.Equal(t, *tc.result, *res)
+		})
+	}
+}
+
+func TestComputeRuleRegistry(t *testing.T) {
+	bucket := schema.GroupKind{Group: "s3.aws.upbound.io", Kind: "Bucket"}
+	rules := NewRuleRegistry()
+	assert.NoError(t, rules.Register(bucket, Rule{
+		Ready: "object.status.conditions.exists(c, c.type == 'Synced' && c.status == 'True')",
+	}))
+	u := testutil.YamlToUnstructured(t, bucketReadyManifest)
+
+	// the rule applies with the registry
+	res, err := Compute(u, WithRuleRegistry(rules))
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Status: metav1.ConditionTrue, Reason: ReasonReady}, *res)
+
+	// the rule is not registered in the DefaultRuleRegistry
+	res, err = Compute(u)
+	assert.NoError(t, err)
+	assert.Equal(t, ReasonInProgress, res.Reason)
+}
+
+func TestRegisterRule(t *testing.T) {
+	cases := map[string]struct {
+		rule    Rule
+		wantErr bool
+	}{
+		"Valid": {
+			rule: Rule{Ready: "object.status.ready == true"},
+		},
+		"MissingReady": {
+			rule:    Rule{Failed: "true"},
+			wantErr: true,
+		},
+		"InvalidSyntax": {
+			rule:    Rule{Ready: "object.status.ready ==="},
+			wantErr: true,
+		},
+		"ReadyNotBool": {
+			rule:    Rule{Ready: "'ready'"},
+			wantErr: true,
+		},
+		"MessageNotString": {
+			rule:    Rule{Ready: "true", Message: "1 + 1"},
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewRuleRegistry()
+			err := r.Register(schema.GroupKind{Group: "example.com", Kind: "Test"}, tc.rule)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
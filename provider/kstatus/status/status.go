@@ -3,6 +3,7 @@ package status
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/clock"
 )
 
 type Reason string
@@ -14,6 +15,8 @@ const (
 	ReasonNoStatusInfo Reason = "NoStatusInfo"
 	ReasonUserManaged  Reason = "UserManaged"
 	ReasonFailed       Reason = "Failed"
+	ReasonNotFound     Reason = "NotFound"
+	ReasonUnknown      Reason = "Unknown"
 )
 
 // A ConditionType represents a condition type for a given KRM resource
@@ -23,20 +26,144 @@ type ConditionType string
 const (
 	// ConditionTypeReady represents the resource ready condition
 	ConditionTypeReady ConditionType = "Ready"
+	// ConditionTypeReconciling represents the abnormal-true condition indicating
+	// the controller is reconciling the resource
+	ConditionTypeReconciling ConditionType = "Reconciling"
+	// ConditionTypeStalled represents the abnormal-true condition indicating
+	// the controller cannot make progress reconciling the resource
+	ConditionTypeStalled ConditionType = "Stalled"
 )
 
 // Result contains the results of a call to compute the status of
 // a resource.
 type Result struct {
-	Status metav1.ConditionStatus
+	Status metav1.ConditionStatus `json:"status" yaml:"status"`
 	// Reason
-	Reason Reason
+	Reason Reason `json:"reason" yaml:"reason"`
 	// Message
-	Message string
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// Checks are the checks the rules evaluated to compute the status, in the
+	// order of evaluation. They are only set with the Explain option.
+	Checks []Check `json:"checks,omitempty" yaml:"checks,omitempty"`
 }
 
-func Compute(u *unstructured.Unstructured) (*Result, error) {
-	res, err := checkGenericProperties(u)
+// Options tune how the status of a resource is computed.
+type Options struct {
+	// AcceptPendingLoadBalancers reports LoadBalancer services as ready when
+	// no load balancer ingress is assigned, e.g. on clusters without a cloud
+	// controller.
+	AcceptPendingLoadBalancers bool
+	// Lookup retrieves the objects related to the resource, e.g. it allows
+	// the workload rules to report container failures of the pods. The rules
+	// skip the checks on related objects when no Lookup is provided.
+	Lookup Lookup
+	// Explain sets the Checks of the Result explaining the status.
+	Explain bool
+	// Clock is used by the rules depending on time, the real clock is used
+	// when not set.
+	Clock clock.PassiveClock
+	// History reports objects that made no progress within a deadline as
+	// failed, no progress is tracked when not set.
+	History *History
+	// MaxLookups is the budget of lookups of a single call to Compute,
+	// DefaultMaxLookups is used when not set.
+	MaxLookups int
+	// Rules is the registry of the rules per GroupKind, the DefaultRuleRegistry
+	// is used when not set.
+	Rules *RuleRegistry
+
+	// checks are recorded by the rules while computing the status
+	checks []Check
+}
+
+// An Option sets an option on the Options.
+type Option func(*Options)
+
+// WithAcceptPendingLoadBalancers sets the AcceptPendingLoadBalancers option.
+func WithAcceptPendingLoadBalancers(accept bool) Option {
+	return func(o *Options) {
+		o.AcceptPendingLoadBalancers = accept
+	}
+}
+
+// WithLookup sets the Lookup option.
+func WithLookup(lookup Lookup) Option {
+	return func(o *Options) {
+		o.Lookup = lookup
+	}
+}
+
+// WithExplain sets the Explain option.
+func WithExplain(explain bool) Option {
+	return func(o *Options) {
+		o.Explain = explain
+	}
+}
+
+// WithClock sets the Clock option.
+func WithClock(c clock.PassiveClock) Option {
+	return func(o *Options) {
+		o.Clock = c
+	}
+}
+
+// WithHistory sets the History option.
+func WithHistory(h *History) Option {
+	return func(o *Options) {
+		o.History = h
+	}
+}
+
+// WithMaxLookups sets the MaxLookups option.
+func WithMaxLookups(max int) Option {
+	return func(o *Options) {
+		o.MaxLookups = max
+	}
+}
+
+// WithRuleRegistry sets the Rules option.
+func WithRuleRegistry(rules *RuleRegistry) Option {
+	return func(o *Options) {
+		o.Rules = rules
+	}
+}
+
+// Compute computes the status of the resource. Rules registered in the rule
+// registry of the options for the GroupKind of the resource take precedence over
+// the generic Ready condition and the built-in rules.
+func Compute(u *unstructured.Unstructured, opts ...Option) (*Result, error) {
+	o := &Options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.Clock == nil {
+		o.Clock = clock.RealClock{}
+	}
+	if o.Rules == nil {
+		o.Rules = DefaultRuleRegistry
+	}
+	res, err := compute(u, o)
+	if err != nil {
+		return nil, err
+	}
+	if o.Explain {
+		res.Checks = o.checks
+	}
+	if o.History != nil {
+		res = o.History.observe(u, res, o.Clock.Now())
+	}
+	return res, nil
+}
+
+func compute(u *unstructured.Unstructured, o *Options) (*Result, error) {
+	if o.Lookup != nil {
+		if o.MaxLookups <= 0 {
+			o.MaxLookups = DefaultMaxLookups
+		}
+		o.Lookup = newCachedLookup(o.Lookup, o.MaxLookups)
+	}
+
+	res, err := checkGenericProperties(u, o)
 	if err != nil {
 		return nil, err
 	}
@@ -46,14 +173,51 @@ func Compute(u *unstructured.Unstructured) (*Result, error) {
 		return res, nil
 	}
 
+	if rule := o.Rules.get(u.GroupVersionKind().GroupKind()); rule != nil {
+		return rule.compute(u, o)
+	}
+
+	res, err = checkGenericConditions(u, o)
+	if err != nil {
+		return nil, err
+	}
 	fn := GetLegacyConditionsFn(u)
+	// The built-in rules refine an in progress result of the generic conditions,
+	// e.g. the Pod rule detects the failed containers of a Pod that is not Ready.
+	if res != nil && (fn == nil || res.Reason != ReasonInProgress) {
+		return res, nil
+	}
 	if fn != nil {
-		return fn(u)
+		return fn(u, o)
+	}
+
+	res = checkGenericPhase(u, o)
+	if res != nil {
+		return res, nil
 	}
 
 	return noStatusInfo(), err
 }
 
+// NotFound returns the result for a resource that does not exist
+func NotFound() *Result {
+	return &Result{
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonNotFound,
+		Message: "resource not found",
+	}
+}
+
+// Unknown returns the result for a resource for which the status cannot
+// be determined
+func Unknown(msg string) *Result {
+	return &Result{
+		Status:  metav1.ConditionUnknown,
+		Reason:  ReasonUnknown,
+		Message: msg,
+	}
+}
+
 func ready(msg string) *Result {
 	return &Result{
 		Status:  metav1.ConditionTrue,
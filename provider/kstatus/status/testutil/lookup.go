@@ -0,0 +1,51 @@
+package testutil
+
+import (
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// FakeLookup is a lookup serving a fixed set of objects, it records the number
+// of lookups it served.
+type FakeLookup struct {
+	Objects []*unstructured.Unstructured
+	Calls   int
+}
+
+// NewFakeLookup returns a FakeLookup serving the objects of the yaml documents.
+func NewFakeLookup(t *testing.T, ymls ...string) *FakeLookup {
+	l := &FakeLookup{}
+	for _, yml := range ymls {
+		l.Objects = append(l.Objects, YamlToUnstructured(t, yml))
+	}
+	return l
+}
+
+func (r *FakeLookup) Get(gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	r.Calls++
+	for _, u := range r.Objects {
+		if u.GroupVersionKind() == gvk && u.GetNamespace() == namespace && u.GetName() == name {
+			return u.DeepCopy(), nil
+		}
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, name)
+}
+
+func (r *FakeLookup) List(gvk schema.GroupVersionKind, namespace string, selector labels.Selector) ([]unstructured.Unstructured, error) {
+	r.Calls++
+	var ul []unstructured.Unstructured
+	for _, u := range r.Objects {
+		if u.GroupVersionKind() != gvk || (namespace != "" && u.GetNamespace() != namespace) {
+			continue
+		}
+		if !selector.Matches(labels.Set(u.GetLabels())) {
+			continue
+		}
+		ul = append(ul, *u.DeepCopy())
+	}
+	return ul, nil
+}
@@ -0,0 +1,217 @@
+// Package statuscmd implements the status command of the provider binary,
+// it computes the status of objects read from files or stdin without a cluster.
+package statuscmd
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
+	"github.com/kform-providers/kubernetes/provider/kstatus/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Name is the name of the command.
+const Name = "status"
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// ErrNotReady is returned with the -exit-code flag when an object is not ready.
+var ErrNotReady = errors.New("not all objects are ready")
+
+// ObjectResult is the status of an object.
+type ObjectResult struct {
+	APIVersion string         `json:"apiVersion"`
+	Kind       string         `json:"kind"`
+	Namespace  string         `json:"namespace,omitempty"`
+	Name       string         `json:"name"`
+	Result     *status.Result `json:"result"`
+}
+
+// Run runs the status command with the arguments following the command name.
+// The objects are read from the files in the arguments, or from stdin when no
+// file or "-" is provided.
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet(Name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [flags] [file ...]\n\n", Name)
+		fmt.Fprintf(fs.Output(), "Computes the status of the objects in the YAML or JSON files, or stdin.\n\n")
+		fs.PrintDefaults()
+	}
+	output := fs.String("o", outputTable, "output format: table or json")
+	explain := fs.Bool("explain", false, "show the checks explaining the status")
+	acceptPendingLoadBalancers := fs.Bool("accept-pending-load-balancers", false, "report LoadBalancer services without ingress as ready")
+	exitCode := fs.Bool("exit-code", false, "exit with an error when an object is not ready")
+	config := fs.String("config", "", "ProviderConfig file with the statusRules, e.g. the provider config of the kform package")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *output != outputTable && *output != outputJSON {
+		return fmt.Errorf("unsupported output format %q, expected %s or %s", *output, outputTable, outputJSON)
+	}
+	rules := status.NewRuleRegistry()
+	if *config != "" {
+		providerConfig, err := readProviderConfig(*config)
+		if err != nil {
+			return err
+		}
+		// the progress deadlines need the history of the object over multiple
+		// observations, the objects are only observed once offline
+		if len(providerConfig.Spec.ProgressDeadlines) > 0 {
+			return fmt.Errorf("progressDeadlines in %s are not supported offline, the status is computed from a single observation", *config)
+		}
+		for _, rule := range providerConfig.Spec.StatusRules {
+			if err := rules.Register(schema.GroupKind{Group: rule.Group, Kind: rule.Kind}, status.Rule{
+				Ready:   rule.Ready,
+				Failed:  rule.Failed,
+				Message: rule.Message,
+			}); err != nil {
+				return fmt.Errorf("invalid status rule in %s: %w", *config, err)
+			}
+		}
+		if providerConfig.Spec.AcceptPendingLoadBalancers != nil && *providerConfig.Spec.AcceptPendingLoadBalancers {
+			*acceptPendingLoadBalancers = true
+		}
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	var objs []*unstructured.Unstructured
+	for _, file := range files {
+		o, err := readObjects(file, stdin)
+		if err != nil {
+			return err
+		}
+		objs = append(objs, o...)
+	}
+
+	opts := []status.Option{
+		status.WithRuleRegistry(rules),
+		status.WithExplain(*explain),
+		status.WithAcceptPendingLoadBalancers(*acceptPendingLoadBalancers),
+	}
+	results := make([]ObjectResult, 0, len(objs))
+	allReady := true
+	for _, u := range objs {
+		res, err := status.Compute(u, opts...)
+		if err != nil {
+			res = status.Unknown(err.Error())
+		}
+		if res.Status != metav1.ConditionTrue {
+			allReady = false
+		}
+		results = append(results, ObjectResult{
+			APIVersion: u.GetAPIVersion(),
+			Kind:       u.GetKind(),
+			Namespace:  u.GetNamespace(),
+			Name:       u.GetName(),
+			Result:     res,
+		})
+	}
+
+	var err error
+	switch *output {
+	case outputJSON:
+		err = printJSON(stdout, results)
+	default:
+		err = printTable(stdout, results)
+	}
+	if err != nil {
+		return err
+	}
+	if *exitCode && !allReady {
+		return ErrNotReady
+	}
+	return nil
+}
+
+// readProviderConfig reads the ProviderConfig from the YAML or JSON file.
+func readProviderConfig(file string) (*v1alpha1.ProviderConfig, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	providerConfig := &v1alpha1.ProviderConfig{}
+	if err := yaml.NewYAMLOrJSONDecoder(f, 4096).Decode(providerConfig); err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %w", file, err)
+	}
+	return providerConfig, nil
+}
+
+// readObjects reads the objects from the YAML or JSON documents in the file,
+// the items of a List are returned as individual objects.
+func readObjects(file string, stdin io.Reader) ([]*unstructured.Unstructured, error) {
+	r := stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var objs []*unstructured.Unstructured
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		m := map[string]interface{}{}
+		if err := decoder.Decode(&m); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return nil, fmt.Errorf("cannot decode %s: %w", file, err)
+		}
+		if len(m) == 0 {
+			continue
+		}
+		u := &unstructured.Unstructured{Object: m}
+		if !u.IsList() {
+			objs = append(objs, u)
+			continue
+		}
+		if err := u.EachListItem(func(o runtime.Object) error {
+			item, ok := o.(*unstructured.Unstructured)
+			if !ok {
+				return fmt.Errorf("unexpected list item %T", o)
+			}
+			objs = append(objs, item)
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("cannot decode list in %s: %w", file, err)
+		}
+	}
+}
+
+func printJSON(w io.Writer, results []ObjectResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+func printTable(w io.Writer, results []ObjectResult) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tNAMESPACE\tNAME\tSTATUS\tREASON\tMESSAGE")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Kind, r.Namespace, r.Name, r.Result.Status, r.Result.Reason, r.Result.Message)
+		for _, c := range r.Result.FailedChecks() {
+			fmt.Fprintf(tw, "\t\t\t\t\t- %s\n", c.String())
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,167 @@
+package statuscmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kform-providers/kubernetes/provider/kstatus/status"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var objects = `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: web
+    namespace: default
+  spec:
+    type: LoadBalancer
+    clusterIP: 10.96.0.10
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web
+  namespace: default
+`
+
+func TestRun(t *testing.T) {
+	cases := map[string]struct {
+		args    []string
+		results []ObjectResult
+		wantErr error
+	}{
+		"NotReady": {
+			args: []string{"-o", "json", "-exit-code"},
+			results: []ObjectResult{
+				{APIVersion: "v1", Kind: "Service", Namespace: "default", Name: "web", Result: &status.Result{
+					Status:  metav1.ConditionFalse,
+					Reason:  status.ReasonInProgress,
+					Message: "LoadBalancer ingress not set. Service type: LoadBalancer",
+				}},
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "web", Result: &status.Result{
+					Status:  metav1.ConditionTrue,
+					Reason:  status.ReasonReady,
+					Message: "ready",
+				}},
+			},
+			wantErr: ErrNotReady,
+		},
+		"AcceptPendingLoadBalancers": {
+			args: []string{"-o", "json", "-exit-code", "-accept-pending-load-balancers", "-"},
+			results: []ObjectResult{
+				{APIVersion: "v1", Kind: "Service", Namespace: "default", Name: "web", Result: &status.Result{
+					Status:  metav1.ConditionTrue,
+					Reason:  status.ReasonReady,
+					Message: "LoadBalancer ingress pending, accepted. Service type: LoadBalancer",
+				}},
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "web", Result: &status.Result{
+					Status:  metav1.ConditionTrue,
+					Reason:  status.ReasonReady,
+					Message: "ready",
+				}},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			err := Run(tc.args, strings.NewReader(objects), stdout, &bytes.Buffer{})
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			results := []ObjectResult{}
+			assert.NoError(t, json.Unmarshal(stdout.Bytes(), &results))
+			assert.Equal(t, tc.results, results)
+		})
+	}
+}
+
+func TestRunTable(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	err := Run(nil, strings.NewReader(objects), stdout, &bytes.Buffer{})
+	assert.NoError(t, err)
+	assert.Equal(t, `KIND       NAMESPACE  NAME  STATUS  REASON      MESSAGE
+Service    default    web   False   InProgress  LoadBalancer ingress not set. Service type: LoadBalancer
+ConfigMap  default    web   True    Ready       ready
+`, stdout.String())
+}
+
+var widget = `
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: w
+  namespace: default
+status:
+  state: Broken
+`
+
+var providerConfig = `
+apiVersion: kubernetes.provider.kform.dev/v1alpha1
+kind: ProviderConfig
+spec:
+  statusRules:
+  - group: example.com
+    kind: Widget
+    ready: object.status.state == 'Ready'
+    failed: object.status.state == 'Broken'
+    message: "'state ' + object.status.state"
+`
+
+func TestRunConfig(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(config, []byte(providerConfig), 0o600))
+
+	stdout := &bytes.Buffer{}
+	err := Run([]string{"-o", "json", "-config", config}, strings.NewReader(widget), stdout, &bytes.Buffer{})
+	assert.NoError(t, err)
+
+	results := []ObjectResult{}
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &results))
+	assert.Equal(t, []ObjectResult{
+		{APIVersion: "example.com/v1", Kind: "Widget", Namespace: "default", Name: "w", Result: &status.Result{
+			Status:  metav1.ConditionFalse,
+			Reason:  status.ReasonFailed,
+			Message: "state Broken",
+		}},
+	}, results)
+}
+
+func TestRunConfigInvalidRule(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(config, []byte(`
+kind: ProviderConfig
+spec:
+  statusRules:
+  - group: example.com
+    kind: Gadget
+    ready: "object.status.("
+`), 0o600))
+
+	err := Run([]string{"-config", config}, strings.NewReader(widget), &bytes.Buffer{}, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "invalid status rule")
+}
+
+func TestRunConfigProgressDeadlines(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(config, []byte(`
+kind: ProviderConfig
+spec:
+  progressDeadlines:
+  - group: apps
+    kind: StatefulSet
+    timeout: 10m
+`), 0o600))
+
+	err := Run([]string{"-config", config}, strings.NewReader(widget), &bytes.Buffer{}, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "progressDeadlines")
+	assert.ErrorContains(t, err, "not supported offline")
+}
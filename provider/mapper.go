@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kform-dev/kform-sdk-go/pkg/diag"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// mapperResetInterval is the minimum interval between resets of the mapper on a
+// mapping miss, to avoid hammering the discovery endpoints with unknown kinds.
+const mapperResetInterval = 2 * time.Second
+
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// resettingMapper is a RESTMapper that refreshes the discovery information when
+// a kind is unknown, as the kind might be served by a CRD installed after the
+// mapper was initialized.
+type resettingMapper struct {
+	meta.RESTMapper
+	discoveryClient discovery.CachedDiscoveryInterface
+	// resetInterval is the minimum interval between resets on a mapping miss
+	resetInterval time.Duration
+
+	m       sync.Mutex
+	resetAt time.Time
+}
+
+func newResettingMapper(mapper meta.RESTMapper, discoveryClient discovery.CachedDiscoveryInterface) *resettingMapper {
+	return &resettingMapper{
+		RESTMapper:      mapper,
+		discoveryClient: discoveryClient,
+		resetInterval:   mapperResetInterval,
+	}
+}
+
+// RESTMapping returns the RESTMapping of the kind, the mapper is reset and the
+// mapping retried when the kind is unknown.
+func (r *resettingMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	m, err := r.RESTMapper.RESTMapping(gk, versions...)
+	if meta.IsNoMatchError(err) && r.maybeReset() {
+		m, err = r.RESTMapper.RESTMapping(gk, versions...)
+	}
+	return m, err
+}
+
+// maybeReset resets the mapper unless it was reset less than the reset
+// interval ago, it returns true when the mapper was reset.
+func (r *resettingMapper) maybeReset() bool {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if time.Since(r.resetAt) < r.resetInterval {
+		return false
+	}
+	r.resetLocked()
+	return true
+}
+
+// Reset invalidates the cached discovery information such that the kinds
+// of a newly established CRD can be mapped.
+func (r *resettingMapper) Reset() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.resetLocked()
+}
+
+func (r *resettingMapper) resetLocked() {
+	r.discoveryClient.Invalidate()
+	meta.MaybeResetRESTMapper(r.RESTMapper)
+	r.resetAt = time.Now()
+}
+
+// getMapping returns the RESTMapping for the provided resource.
+func (r *Client) getMapping(obj *unstructured.Unstructured) (*meta.RESTMapping, error) {
+	gvk := obj.GroupVersionKind()
+	return r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+}
+
+// resetMapperForCRD resets the mapper when the object is a CRD, the CRD is
+// established when the status of the object is ready.
+func (r *Client) resetMapperForCRD(u *unstructured.Unstructured) {
+	if u != nil && u.GroupVersionKind().GroupKind() == crdGroupKind {
+		r.mapper.Reset()
+	}
+}
+
+// recordCRD records the kind provided by the object when it is a CRD, such that
+// the custom resources of a CRD of the same config can be deferred.
+func (r *Client) recordCRD(u *unstructured.Unstructured) {
+	if u.GroupVersionKind().GroupKind() != crdGroupKind {
+		return
+	}
+	group, _, _ := unstructured.NestedString(u.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(u.Object, "spec", "names", "kind")
+	if kind == "" {
+		return
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.crdKinds.Insert(schema.GroupKind{Group: group, Kind: kind})
+}
+
+// providedByCRD returns true when the kind is provided by a CRD of the config or
+// by a CRD of the cluster that is not yet established.
+func (r *Client) providedByCRD(ctx context.Context, gk schema.GroupKind) (bool, error) {
+	r.m.Lock()
+	recorded := r.crdKinds.Has(gk)
+	r.m.Unlock()
+	if recorded {
+		return true, nil
+	}
+	crds := &unstructured.UnstructuredList{}
+	crds.SetGroupVersionKind(crdGroupKind.WithVersion("v1").GroupVersion().WithKind("CustomResourceDefinitionList"))
+	if err := r.List(ctx, crds); err != nil {
+		return false, err
+	}
+	for _, crd := range crds.Items {
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		if (schema.GroupKind{Group: group, Kind: kind}) == gk {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// deferUnservedKind returns the object unchanged with a warning when its kind is
+// not served by the cluster but provided by a CRD of the config or a pending CRD,
+// such that a dry-run of a custom resource whose CRD is installed by the same
+// config does not fail. The object gets validated when it is applied, after the
+// CRD got established. The mapping error is returned when no CRD provides the
+// kind. It returns false when the kind is served or the mapping failed for
+// another reason.
+func (r *Client) deferUnservedKind(ctx context.Context, u *unstructured.Unstructured) ([]byte, diag.Diagnostics, bool) {
+	_, err := r.getMapping(u)
+	if !meta.IsNoMatchError(err) {
+		return nil, nil, false
+	}
+	gvk := u.GroupVersionKind()
+	provided, perr := r.providedByCRD(ctx, gvk.GroupKind())
+	if perr != nil {
+		return nil, diag.FromErr(fmt.Errorf("%w, cannot list the CRDs providing the kind: %v", err, perr)), true
+	}
+	if !provided {
+		return nil, diag.FromErr(err), true
+	}
+	b, err := json.Marshal(u)
+	if err != nil {
+		return nil, diag.FromErr(err), true
+	}
+	return b, diag.Diagnostics{diag.DiagWarnfWithContext(
+		fmt.Sprintf("%s/%s", gvk.Kind, u.GetName()),
+		"deferred: kind not yet served, apiVersion %s kind %s is validated at apply time", gvk.GroupVersion().String(), gvk.Kind).Get()}, true
+}
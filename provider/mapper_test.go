@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kform-providers/kubernetes/provider/client"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// newTestClient returns a Client backed by a fake dynamic client with the objects,
+// the mapper maps the CRDs and the kinds.
+func newTestClient(gvks map[schema.GroupVersionKind]meta.RESTScope, objs ...runtime.Object) *Client {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(crdGroupKind.WithVersion("v1").GroupVersion().WithKind(crdGroupKind.Kind), meta.RESTScopeRoot)
+	listKinds := map[schema.GroupVersionResource]string{
+		crdGVR: "CustomResourceDefinitionList",
+	}
+	for gvk, scope := range gvks {
+		mapper.Add(gvk, scope)
+		m, _ := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		listKinds[m.Resource] = gvk.Kind + "List"
+	}
+	dc := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objs...)
+	rm := newResettingMapper(mapper, memory.NewMemCacheClient(&fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{}}))
+	return &Client{
+		Client:   client.New(dc, rm),
+		dc:       dc,
+		mapper:   rm,
+		crdKinds: sets.New[schema.GroupKind](),
+	}
+}
+
+func newCRD(group, kind string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata": map[string]interface{}{
+			"name": "widgets." + group,
+		},
+		"spec": map[string]interface{}{
+			"group": group,
+			"names": map[string]interface{}{
+				"kind":   kind,
+				"plural": "widgets",
+			},
+		},
+	}}
+}
+
+func newWidget() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("example.com/v1")
+	u.SetKind("Widget")
+	u.SetNamespace("default")
+	u.SetName("w")
+	return u
+}
+
+func TestDeferUnservedKind(t *testing.T) {
+	cases := map[string]struct {
+		served    bool
+		recorded  *unstructured.Unstructured
+		cluster   []runtime.Object
+		deferred  bool
+		errorDiag bool
+	}{
+		"Served": {
+			served: true,
+		},
+		"CRDOfConfig": {
+			recorded: newCRD("example.com", "Widget"),
+			deferred: true,
+		},
+		"PendingCRD": {
+			cluster:  []runtime.Object{newCRD("example.com", "Widget")},
+			deferred: true,
+		},
+		"OtherCRD": {
+			recorded:  newCRD("example.com", "Gadget"),
+			cluster:   []runtime.Object{newCRD("other.com", "Widget")},
+			deferred:  true,
+			errorDiag: true,
+		},
+		"NoCRD": {
+			deferred:  true,
+			errorDiag: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gvks := map[schema.GroupVersionKind]meta.RESTScope{}
+			if tc.served {
+				gvks[newWidget().GroupVersionKind()] = meta.RESTScopeNamespace
+			}
+			c := newTestClient(gvks, tc.cluster...)
+			if tc.recorded != nil {
+				c.recordCRD(tc.recorded)
+			}
+
+			b, diags, deferred := c.deferUnservedKind(context.Background(), newWidget())
+			assert.Equal(t, tc.deferred, deferred)
+			assert.Equal(t, tc.errorDiag, diags.HasError())
+			if tc.deferred && !tc.errorDiag {
+				assert.NotEmpty(t, b)
+				assert.Len(t, diags, 1)
+			}
+		})
+	}
+}
+
+// countingDiscovery counts the invalidations of the cached discovery information.
+type countingDiscovery struct {
+	discovery.CachedDiscoveryInterface
+	invalidations int
+}
+
+func (r *countingDiscovery) Invalidate() {
+	r.invalidations++
+}
+
+func TestResettingMapperMaybeReset(t *testing.T) {
+	dc := &countingDiscovery{}
+	mapper := newResettingMapper(meta.NewDefaultRESTMapper(nil), dc)
+
+	// the first miss resets the mapper
+	_, err := mapper.RESTMapping(schema.GroupKind{Group: "example.com", Kind: "Widget"}, "v1")
+	assert.True(t, meta.IsNoMatchError(err))
+	assert.Equal(t, 1, dc.invalidations)
+
+	// the misses within the reset interval do not reset the mapper
+	assert.False(t, mapper.maybeReset())
+	_, err = mapper.RESTMapping(schema.GroupKind{Group: "example.com", Kind: "Gadget"}, "v1")
+	assert.True(t, meta.IsNoMatchError(err))
+	assert.Equal(t, 1, dc.invalidations)
+
+	// an explicit reset is not rate limited
+	mapper.Reset()
+	assert.Equal(t, 2, dc.invalidations)
+
+	// a miss after the reset interval resets the mapper
+	mapper.resetInterval = time.Millisecond
+	time.Sleep(2 * time.Millisecond)
+	assert.True(t, mapper.maybeReset())
+	assert.Equal(t, 3, dc.invalidations)
+}
+
+func TestResettingMapperServed(t *testing.T) {
+	dc := &countingDiscovery{}
+	rm := meta.NewDefaultRESTMapper(nil)
+	rm.Add(configMapGVK, meta.RESTScopeNamespace)
+	mapper := newResettingMapper(rm, dc)
+
+	m, err := mapper.RESTMapping(configMapGVK.GroupKind(), configMapGVK.Version)
+	assert.NoError(t, err)
+	assert.Equal(t, "configmaps", m.Resource.Resource)
+	assert.Equal(t, 0, dc.invalidations)
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// getNamespace returns the namespace of the provider config, the namespace of the
+// kubeconfig context is the default namespace unless the provider config defines it.
+func getNamespace(kubeConfig clientcmd.ClientConfig, namespace *string) (string, error) {
+	if namespace != nil && *namespace != "" {
+		return *namespace, nil
+	}
+	ns, _, err := kubeConfig.Namespace()
+	return ns, err
+}
+
+// defaultNamespace sets the namespace of the provider on a namespaced object without
+// namespace. A namespace set on a cluster-scoped object fails the write, the create
+// and update, instead of being ignored; otherwise it is cleared, such that an object
+// recorded with a namespace can still be read and deleted. Objects whose kind cannot
+// be mapped are left untouched, the mapping error is reported by the request of the
+// operation.
+func (r *Client) defaultNamespace(u *unstructured.Unstructured, write bool) error {
+	m, err := r.getMapping(u)
+	if err != nil {
+		return nil
+	}
+	if m.Scope.Name() != meta.RESTScopeNameNamespace {
+		if u.GetNamespace() != "" {
+			if write {
+				return fmt.Errorf("kind %s is cluster-scoped, namespace %s must not be set on %s", u.GetKind(), u.GetNamespace(), u.GetName())
+			}
+			u.SetNamespace("")
+		}
+		return nil
+	}
+	if u.GetNamespace() == "" {
+		u.SetNamespace(r.namespace)
+	}
+	return nil
+}
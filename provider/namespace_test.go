@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	sdkschema "github.com/kform-dev/kform-sdk-go/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/utils/ptr"
+)
+
+var namespaceGVK = schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+
+func TestGetNamespace(t *testing.T) {
+	kubeConfig := func(namespace string) clientcmd.ClientConfig {
+		return clientcmd.NewDefaultClientConfig(clientcmdapi.Config{
+			CurrentContext: "kind",
+			Contexts:       map[string]*clientcmdapi.Context{"kind": {Cluster: "kind", Namespace: namespace}},
+			Clusters:       map[string]*clientcmdapi.Cluster{"kind": {Server: "https://127.0.0.1:6443"}},
+		}, &clientcmd.ConfigOverrides{})
+	}
+
+	cases := map[string]struct {
+		kubeConfig clientcmd.ClientConfig
+		namespace  *string
+		expected   string
+	}{
+		"ProviderConfig": {
+			kubeConfig: kubeConfig("team-a"),
+			namespace:  ptr.To("team-b"),
+			expected:   "team-b",
+		},
+		"KubeConfigContext": {
+			kubeConfig: kubeConfig("team-a"),
+			expected:   "team-a",
+		},
+		"EmptyProviderConfig": {
+			kubeConfig: kubeConfig("team-a"),
+			namespace:  ptr.To(""),
+			expected:   "team-a",
+		},
+		"Default": {
+			kubeConfig: kubeConfig(""),
+			expected:   "default",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			namespace, err := getNamespace(tc.kubeConfig, tc.namespace)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, namespace)
+		})
+	}
+}
+
+func TestDefaultNamespace(t *testing.T) {
+	namespace := func(name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(namespaceGVK)
+		u.SetName(name)
+		return u
+	}
+
+	cases := map[string]struct {
+		obj      *unstructured.Unstructured
+		write    bool
+		expected string
+		err      bool
+	}{
+		"Namespaced": {
+			obj:      func() *unstructured.Unstructured { u := newConfigMap("web"); u.SetNamespace(""); return u }(),
+			expected: "team-a",
+		},
+		"NamespacedWithNamespace": {
+			obj:      newConfigMap("web"),
+			expected: "default",
+		},
+		"ClusterScoped": {
+			obj: namespace("team-b"),
+		},
+		"ClusterScopedWithNamespace": {
+			obj:   func() *unstructured.Unstructured { u := namespace("team-b"); u.SetNamespace("team-a"); return u }(),
+			write: true,
+			err:   true,
+		},
+		// the object can still be read and deleted
+		"ClusterScopedWithNamespaceRead": {
+			obj: func() *unstructured.Unstructured { u := namespace("team-b"); u.SetNamespace("team-a"); return u }(),
+		},
+		// the mapping error is reported by the request of the operation
+		"Unmapped": {
+			obj:      newWidget(),
+			expected: "default",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(map[schema.GroupVersionKind]meta.RESTScope{
+				configMapGVK: meta.RESTScopeNamespace,
+				namespaceGVK: meta.RESTScopeRoot,
+			})
+			c.namespace = "team-a"
+			err := c.defaultNamespace(tc.obj, tc.write)
+			if tc.err {
+				assert.ErrorContains(t, err, "cluster-scoped")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, tc.obj.GetNamespace())
+		})
+	}
+}
+
+func TestClusterScopedWithNamespace(t *testing.T) {
+	ns := &unstructured.Unstructured{}
+	ns.SetGroupVersionKind(namespaceGVK)
+	ns.SetName("team-b")
+	c := newTestClient(map[schema.GroupVersionKind]meta.RESTScope{namespaceGVK: meta.RESTScopeRoot}, ns)
+	c.defaults = &objectDefaults{}
+
+	u := ns.DeepCopy()
+	u.SetNamespace("team-a")
+	b, err := json.Marshal(u)
+	assert.NoError(t, err)
+	obj := &sdkschema.ResourceObject{Obj: b}
+
+	_, diags := resourceKubernetesManifestCreate(context.Background(), obj, c)
+	assert.True(t, diags.HasError())
+
+	b, diags = resourceKubernetesManifestRead(context.Background(), obj, c)
+	assert.False(t, diags.HasError())
+	assert.NotEmpty(t, b)
+
+	diags = resourceKubernetesManifestDelete(context.Background(), obj, c)
+	assert.False(t, diags.HasError())
+}
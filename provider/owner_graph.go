@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/henderiw/logger/log"
+	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
+	"github.com/kform-providers/kubernetes/provider/kstatus/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const defaultOwnerGraphMaxDepth = 10
+
+// ownerGraph resolves the dependents of the root object by walking the ownerReferences
+// of the objects of the supplied kinds. When no kinds are supplied all namespaced kinds
+// that can be listed are discovered. Dependents of a namespaced root are looked up in
+// the namespace of the root, dependents of a cluster-scoped root in all namespaces.
+func (r *Client) ownerGraph(ctx context.Context, root *unstructured.Unstructured, kinds []schema.GroupKind, maxDepth int) (*v1alpha1.OwnerGraphNode, error) {
+	log := log.FromContext(ctx)
+
+	resources, err := r.getNamespacedResources(kinds)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := map[types.UID][]*unstructured.Unstructured{}
+	for _, gvr := range resources {
+		ul, err := r.dc.Resource(gvr).Namespace(root.GetNamespace()).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) || apierrors.IsMethodNotSupported(err) {
+				log.Debug("cannot list resource, skipping", "gvr", gvr.String(), "err", err.Error())
+				continue
+			}
+			return nil, err
+		}
+		for i := range ul.Items {
+			o := &ul.Items[i]
+			for _, ref := range o.GetOwnerReferences() {
+				owned[ref.UID] = append(owned[ref.UID], o)
+			}
+		}
+	}
+	return r.newOwnerGraphNode(ctx, root, owned, sets.New[types.UID](), maxDepth), nil
+}
+
+// getNamespacedResources returns the resources of the namespaced kinds. When no kinds
+// are supplied all namespaced resources that support list are discovered.
+func (r *Client) getNamespacedResources(kinds []schema.GroupKind) ([]schema.GroupVersionResource, error) {
+	resources := []schema.GroupVersionResource{}
+	if len(kinds) > 0 {
+		for _, gk := range kinds {
+			m, err := r.mapper.RESTMapping(gk)
+			if err != nil {
+				return nil, err
+			}
+			if m.Scope != meta.RESTScopeNamespace {
+				continue
+			}
+			resources = append(resources, m.Resource)
+		}
+		return resources, nil
+	}
+
+	// partial discovery failures are tolerated as long as some resources are discovered
+	lists, err := r.discoveryClient.ServerPreferredNamespacedResources()
+	if err != nil && len(lists) == 0 {
+		return nil, err
+	}
+	for _, l := range lists {
+		gv, err := schema.ParseGroupVersion(l.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, res := range l.APIResources {
+			// subresources cannot be listed and events never have owners
+			if strings.Contains(res.Name, "/") || res.Name == "events" {
+				continue
+			}
+			if !sets.New(res.Verbs...).Has("list") {
+				continue
+			}
+			resources = append(resources, gv.WithResource(res.Name))
+		}
+	}
+	return resources, nil
+}
+
+func (r *Client) newOwnerGraphNode(ctx context.Context, u *unstructured.Unstructured, owned map[types.UID][]*unstructured.Unstructured, visited sets.Set[types.UID], depth int) *v1alpha1.OwnerGraphNode {
+	visited.Insert(u.GetUID())
+	node := &v1alpha1.OwnerGraphNode{
+		ObjectReference: v1alpha1.ObjectReference{
+			APIVersion: u.GetAPIVersion(),
+			Kind:       u.GetKind(),
+			Namespace:  u.GetNamespace(),
+			Name:       u.GetName(),
+		},
+		UID: u.GetUID(),
+	}
+	result, err := r.computeStatus(ctx, u)
+	if err != nil {
+		result = status.Unknown(err.Error())
+	}
+	node.Result = statusResult(result)
+	if depth <= 0 {
+		return node
+	}
+	for _, o := range owned[u.GetUID()] {
+		if visited.Has(o.GetUID()) {
+			continue
+		}
+		node.Dependents = append(node.Dependents, r.newOwnerGraphNode(ctx, o, owned, visited, depth-1))
+	}
+	sort.SliceStable(node.Dependents, func(i, j int) bool {
+		if node.Dependents[i].Kind != node.Dependents[j].Kind {
+			return node.Dependents[i].Kind < node.Dependents[j].Kind
+		}
+		return node.Dependents[i].Name < node.Dependents[j].Name
+	})
+	return node
+}
+
+// statusResult converts the computed status to its API type.
+func statusResult(res *status.Result) *v1alpha1.StatusResult {
+	r := &v1alpha1.StatusResult{
+		Status:  res.Status,
+		Reason:  string(res.Reason),
+		Message: res.Message,
+	}
+	for _, c := range res.Checks {
+		r.Checks = append(r.Checks, v1alpha1.StatusCheck{
+			Name:               c.Name,
+			Passed:             c.Passed,
+			Observed:           c.Observed,
+			Expected:           c.Expected,
+			Message:            c.Message,
+			LastTransitionTime: c.LastTransitionTime,
+		})
+	}
+	return r
+}
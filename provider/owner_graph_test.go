@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/kform-dev/kform-sdk-go/pkg/schema"
+	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
+	"github.com/kform-providers/kubernetes/provider/kstatus/status"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kschema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestOwnerGraphRootNotFound(t *testing.T) {
+	c := newTestClient(map[kschema.GroupVersionKind]meta.RESTScope{configMapGVK: meta.RESTScopeNamespace})
+	root := v1alpha1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "missing"}
+	b, err := json.Marshal(&v1alpha1.OwnerGraph{Spec: v1alpha1.OwnerGraphSpec{Root: root}})
+	assert.NoError(t, err)
+
+	b, diags := dataSourceKubernetesOwnerGraphRead(context.Background(), &schema.ResourceObject{Obj: b}, c)
+	assert.False(t, diags.HasError())
+
+	graph := &v1alpha1.OwnerGraph{}
+	assert.NoError(t, json.Unmarshal(b, graph))
+	assert.Equal(t, &v1alpha1.OwnerGraphNode{ObjectReference: root, Result: statusResult(status.NotFound())}, graph.Status.Root)
+}
+
+// newOwnedConfigMap returns a ConfigMap with the uid owned by the owner uids.
+func newOwnedConfigMap(name string, owners ...string) *unstructured.Unstructured {
+	u := newConfigMap(name)
+	u.SetUID(types.UID(name))
+	refs := []metav1.OwnerReference{}
+	for _, owner := range owners {
+		refs = append(refs, metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: owner, UID: types.UID(owner)})
+	}
+	u.SetOwnerReferences(refs)
+	return u
+}
+
+// graphNames returns the names of the nodes of the graph, the dependents of a
+// node are listed in parentheses.
+func graphNames(node *v1alpha1.OwnerGraphNode) string {
+	s := node.Name
+	if len(node.Dependents) == 0 {
+		return s
+	}
+	s += "("
+	for i, d := range node.Dependents {
+		if i > 0 {
+			s += " "
+		}
+		s += graphNames(d)
+	}
+	return s + ")"
+}
+
+func TestNewOwnerGraphNode(t *testing.T) {
+	objs := []*unstructured.Unstructured{
+		newOwnedConfigMap("b", "a"),
+		newOwnedConfigMap("c", "a"),
+		newOwnedConfigMap("d", "c"),
+		newOwnedConfigMap("e", "d"),
+		// a cycle of ownerReferences is not followed
+		newOwnedConfigMap("a", "e"),
+	}
+	owned := map[types.UID][]*unstructured.Unstructured{}
+	for _, o := range objs {
+		for _, ref := range o.GetOwnerReferences() {
+			owned[ref.UID] = append(owned[ref.UID], o)
+		}
+	}
+
+	cases := map[string]struct {
+		depth    int
+		expected string
+	}{
+		"Unlimited": {
+			depth:    defaultOwnerGraphMaxDepth,
+			expected: "a(b c(d(e)))",
+		},
+		"DepthLimit": {
+			depth:    2,
+			expected: "a(b c(d))",
+		},
+		"RootOnly": {
+			depth:    0,
+			expected: "a",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(map[kschema.GroupVersionKind]meta.RESTScope{configMapGVK: meta.RESTScopeNamespace})
+			node := c.newOwnerGraphNode(context.Background(), newOwnedConfigMap("a"), owned, sets.New[types.UID](), tc.depth)
+			assert.Equal(t, tc.expected, graphNames(node))
+			assert.Equal(t, v1alpha1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "a"}, node.ObjectReference)
+			assert.Equal(t, types.UID("a"), node.UID)
+			assert.Equal(t, metav1.ConditionTrue, node.Result.Status)
+			assert.Equal(t, "Ready", node.Result.Reason)
+		})
+	}
+}
+
+func TestStatusResult(t *testing.T) {
+	res := &status.Result{
+		Status:  metav1.ConditionFalse,
+		Reason:  status.ReasonInProgress,
+		Message: "Ready: 1/3",
+		Checks:  []status.Check{{Name: "readyReplicas", Observed: "1", Expected: "3"}},
+	}
+	assert.Equal(t, &v1alpha1.StatusResult{
+		Status:  metav1.ConditionFalse,
+		Reason:  "InProgress",
+		Message: "Ready: 1/3",
+		Checks:  []v1alpha1.StatusCheck{{Name: "readyReplicas", Observed: "1", Expected: "3"}},
+	}, statusResult(res))
+}
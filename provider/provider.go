@@ -4,19 +4,29 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/henderiw/logger/log"
 	"github.com/kform-dev/kform-sdk-go/pkg/diag"
 	kformschema "github.com/kform-dev/kform-sdk-go/pkg/schema"
 	"github.com/kform-providers/kubernetes/provider/api/v1alpha1"
-	"k8s.io/apimachinery/pkg/api/meta"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"github.com/kform-providers/kubernetes/provider/client"
+	"github.com/kform-providers/kubernetes/provider/deprecation"
+	"github.com/kform-providers/kubernetes/provider/kstatus/status"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/kubectl/pkg/cmd/util"
 	"sigs.k8s.io/cli-utils/pkg/flowcontrol"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func Provider() *kformschema.Provider {
@@ -26,7 +36,12 @@ func Provider() *kformschema.Provider {
 			"kubernetes_manifest": resourceKubernetesManifest(),
 		},
 		DataSourcesMap: map[string]*kformschema.Resource{
-			"kubernetes_manifest": dataSourceKubernetesManifest(),
+			"kubernetes_manifest":      dataSourceKubernetesManifest(),
+			"kubernetes_access_review": dataSourceKubernetesAccessReview(),
+			"kubernetes_wait":          dataSourceKubernetesWait(),
+			"kubernetes_secret":        dataSourceKubernetesSecret(),
+			"kubernetes_owner_graph":   dataSourceKubernetesOwnerGraph(),
+			"kubernetes_events":        dataSourceKubernetesEvents(),
 		},
 		ListDataSourcesMap: map[string]*kformschema.Resource{
 			"kubernetes_manifest": dataSourcesKubernetesManifest(),
@@ -45,6 +60,67 @@ func providerConfigure(ctx context.Context, d []byte, _ string) (any, diag.Diagn
 		return nil, diag.FromErr(err)
 	}
 
+	// the rules are registered per client, such that the rules of one provider
+	// config do not apply to the objects of another
+	rules := status.NewRuleRegistry()
+	for _, rule := range providerConfig.Spec.StatusRules {
+		if err := rules.Register(schema.GroupKind{Group: rule.Group, Kind: rule.Kind}, status.Rule{
+			Ready:   rule.Ready,
+			Failed:  rule.Failed,
+			Message: rule.Message,
+		}); err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
+
+	progressDeadlines := map[schema.GroupKind]time.Duration{}
+	for _, d := range providerConfig.Spec.ProgressDeadlines {
+		progressDeadlines[schema.GroupKind{Group: d.Group, Kind: d.Kind}] = d.Timeout.Duration
+	}
+
+	var fieldValidation client.FieldValidation
+	if providerConfig.Spec.FieldValidation != nil {
+		fieldValidation = client.FieldValidation(*providerConfig.Spec.FieldValidation)
+		if !fieldValidation.Valid() {
+			return nil, diag.Errorf("invalid fieldValidation %q, expected Ignore, Warn or Strict", fieldValidation)
+		}
+	}
+
+	defaultLabels := providerConfig.Spec.DefaultLabels
+	var applyOpts []client.ApplyOption
+	adoptExisting := false
+	if apply := providerConfig.Spec.Apply; apply != nil {
+		adoptExisting = apply.AdoptExisting != nil && *apply.AdoptExisting
+		if apply.RequireManagedByKform != nil && *apply.RequireManagedByKform {
+			applyOpts = append(applyOpts, client.MustBeManagedByKform())
+			// the objects created and updated by kform are labeled as managed by kform
+			defaultLabels = map[string]string{client.LabelManagedBy: client.ManagedByKform}
+			for k, v := range providerConfig.Spec.DefaultLabels {
+				defaultLabels[k] = v
+			}
+		}
+		if apply.RetainLabels != nil && *apply.RetainLabels {
+			applyOpts = append(applyOpts, client.RetainLabels())
+		}
+		if len(apply.PreserveFields) > 0 {
+			applyOpts = append(applyOpts, client.PreserveFields(apply.PreserveFields...))
+		}
+	}
+
+	for k, v := range defaultLabels {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return nil, diag.Errorf("invalid defaultLabels key %q: %s", k, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return nil, diag.Errorf("invalid defaultLabels value %q of key %s: %s", v, k, strings.Join(errs, "; "))
+		}
+	}
+	for k := range providerConfig.Spec.DefaultAnnotations {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return nil, diag.Errorf("invalid defaultAnnotations key %q: %s", k, strings.Join(errs, "; "))
+		}
+	}
+
 	kubeConfigFlags := genericclioptions.NewConfigFlags(true).WithDeprecatedPasswordFlag()
 	if providerConfig.Spec.ConfigPath != nil {
 		kubeConfigFlags.KubeConfig = providerConfig.Spec.ConfigPath
@@ -56,17 +132,25 @@ func providerConfigure(ctx context.Context, d []byte, _ string) (any, diag.Diagn
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
+	namespace, err := getNamespace(f.ToRawKubeConfigLoader(), providerConfig.Spec.Namespace)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
 	enabled, err := flowcontrol.IsEnabled(ctx, restConfig)
 	if err != nil {
 		return nil, diag.FromErr(fmt.Errorf("checking server-side throttling enablement: %w", err))
 	}
-	if enabled {
-		// WrapConfigFn will affect future Factory.ToRESTConfig() calls.
-		kubeConfigFlags.WrapConfigFn = func(cfg *rest.Config) *rest.Config {
+	// WrapConfigFn will affect future Factory.ToRESTConfig() calls.
+	kubeConfigFlags.WrapConfigFn = func(cfg *rest.Config) *rest.Config {
+		if enabled {
 			cfg.QPS = -1
 			cfg.Burst = -1
-			return cfg
 		}
+		// the warnings are returned as diagnostics of the resource operations
+		// instead of being logged
+		cfg.WarningHandler = rest.NoWarnings{}
+		cfg.Wrap(newWarningTransport)
+		return cfg
 	}
 
 	dc, err := f.DynamicClient()
@@ -81,6 +165,12 @@ func providerConfigure(ctx context.Context, d []byte, _ string) (any, diag.Diagn
 		return nil, diag.FromErr(err)
 	}
 
+	discoveryClient, err := f.ToDiscoveryClient()
+	if err != nil {
+		log.Error("cannot get discovery client", "error", err.Error())
+		return nil, diag.FromErr(err)
+	}
+
 	/*
 		cfg := ctrl.GetConfigOrDie()
 		cfg.UserAgent = fmt.Sprintf("K8sForm/%s", version)
@@ -137,11 +227,34 @@ func providerConfigure(ctx context.Context, d []byte, _ string) (any, diag.Diagn
 		}
 	*/
 
+	rm := newResettingMapper(mapper, discoveryClient)
 	return &Client{
 		//f:               f,
-		dc:     dc,
-		mapper: mapper,
-		//discoveryClient: discoveryClient,
+		Client:                    client.WithRetry(client.New(dc, rm), client.DefaultBackoff),
+		dc:                        dc,
+		mapper:                    rm,
+		discoveryClient:           discoveryClient,
+		namespace:                 namespace,
+		preflightAccessReview:     providerConfig.Spec.PreflightAccessReview != nil && *providerConfig.Spec.PreflightAccessReview,
+		fieldValidation:           fieldValidation,
+		applyOpts:                 applyOpts,
+		adoptExisting:             adoptExisting,
+		autoConvertDeprecatedAPIs: providerConfig.Spec.AutoConvertDeprecatedAPIs != nil && *providerConfig.Spec.AutoConvertDeprecatedAPIs,
+		defaults: &objectDefaults{
+			labels:       defaultLabels,
+			annotations:  providerConfig.Spec.DefaultAnnotations,
+			podTemplates: providerConfig.Spec.PropagateDefaultsToPodTemplates != nil && *providerConfig.Spec.PropagateDefaultsToPodTemplates,
+		},
+		progressDeadlines: progressDeadlines,
+		statusOpts: []status.Option{
+			status.WithRuleRegistry(rules),
+			status.WithExplain(true),
+			status.WithHistory(status.NewHistory(progressDeadlines)),
+			status.WithAcceptPendingLoadBalancers(providerConfig.Spec.AcceptPendingLoadBalancers != nil && *providerConfig.Spec.AcceptPendingLoadBalancers),
+		},
+		accessReviews: map[v1alpha1.AccessCheck]*v1alpha1.AccessCheckResult{},
+		warnings:      map[string]struct{}{},
+		crdKinds:      sets.New[schema.GroupKind](),
 	}, diag.Diagnostics{}
 }
 
@@ -270,92 +383,89 @@ func initializeConfiguration(_ context.Context, providerConfig *v1alpha1.Provide
 */
 
 type Client struct {
-	dc dynamic.Interface
-	//discoveryClient discovery.CachedDiscoveryInterface
-	mapper meta.RESTMapper
+	client.Client
+	dc              dynamic.Interface
+	discoveryClient discovery.CachedDiscoveryInterface
+	mapper          *resettingMapper
+
+	// namespace of the namespaced objects without namespace
+	namespace             string
+	preflightAccessReview bool
+	fieldValidation       client.FieldValidation
+	// applyOpts are the options of the apply of the manifests to existing objects
+	applyOpts []client.ApplyOption
+	// adoptExisting updates the existing objects on create
+	adoptExisting bool
+	// autoConvertDeprecatedAPIs replaces removed apiVersions by their replacement
+	autoConvertDeprecatedAPIs bool
+	defaults                  *objectDefaults
+	statusOpts                []status.Option
+	// progressDeadlines per GroupKind, the status of their resources is polled
+	// until the resource timeout to report the resources without progress
+	progressDeadlines map[schema.GroupKind]time.Duration
+
+	m             sync.Mutex
+	accessReviews map[v1alpha1.AccessCheck]*v1alpha1.AccessCheckResult
+	// crdKinds are the kinds provided by the CRDs of the config
+	crdKinds sets.Set[schema.GroupKind]
+	// warnings that were returned as diagnostics, they are returned once per run
+	warnings map[string]struct{}
+	// serverVersion of the cluster
+	serverVersion *deprecation.MinorVersion
 }
 
-// getMapping returns the RESTMapping for the provided resource.
-func (r *Client) getMapping(obj *unstructured.Unstructured) (*meta.RESTMapping, error) {
-	return r.mapper.RESTMapping(obj.GroupVersionKind().GroupKind(), obj.GroupVersionKind().Version)
+// computeStatus computes the status of the resource with the status options
+// of the provider.
+func (r *Client) computeStatus(ctx context.Context, u *unstructured.Unstructured) (*status.Result, error) {
+	opts := append([]status.Option{status.WithLookup(&statusLookup{ctx: ctx, client: r})}, r.statusOpts...)
+	return status.Compute(u, opts...)
 }
 
-func (r *Client) Get(ctx context.Context, obj *unstructured.Unstructured, options metav1.GetOptions) (*unstructured.Unstructured, error) {
-	m, err := r.getMapping(obj)
-	if err != nil {
-		return nil, err
+// writer returns the client of the create and update of a manifest, it writes
+// with the dry-run and the field validation of the operation.
+func (r *Client) writer(dryRun bool, fieldValidation client.FieldValidation) client.Client {
+	opts := []client.WriteOption{fieldValidation}
+	if dryRun {
+		opts = append(opts, ctrlclient.DryRunAll)
 	}
+	return client.WithWriteOptions(r.Client, opts...)
+}
 
-	var newObj *unstructured.Unstructured
-	if m.Scope == meta.RESTScopeNamespace {
-		if obj.GetNamespace() == "" {
-			return nil, fmt.Errorf("expected namespace, got %s", obj.GetNamespace())
-		}
-		newObj, err = r.dc.Resource(m.Resource).Namespace(obj.GetNamespace()).Get(ctx, obj.GetName(), options)
-	} else {
-		newObj, err = r.dc.Resource(m.Resource).Get(ctx, obj.GetName(), options)
-	}
-	if err != nil {
-		return nil, err
-	}
-	return newObj, nil
+// statusLookup implements the status.Lookup with the client of the provider,
+// such that the lookups are retried on transient errors.
+type statusLookup struct {
+	ctx    context.Context
+	client *Client
 }
 
-func (r *Client) Create(ctx context.Context, obj *unstructured.Unstructured, options metav1.CreateOptions) (*unstructured.Unstructured, error) {
-	m, err := r.getMapping(obj)
-	if err != nil {
+func (r *statusLookup) Get(gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	if err := r.client.Get(r.ctx, ctrlclient.ObjectKey{Namespace: namespace, Name: name}, u); err != nil {
 		return nil, err
 	}
-	var newObj *unstructured.Unstructured
-	if m.Scope == meta.RESTScopeNamespace {
-		if obj.GetNamespace() == "" {
-			return nil, fmt.Errorf("expected namespace, got %s", obj.GetNamespace())
-		}
-		newObj, err = r.dc.Resource(m.Resource).Namespace(obj.GetNamespace()).Create(ctx, obj, options)
-	} else {
-		newObj, err = r.dc.Resource(m.Resource).Create(ctx, obj, options)
-	}
-	if err != nil {
+	return u, nil
+}
+
+func (r *statusLookup) List(gvk schema.GroupVersionKind, namespace string, selector labels.Selector) ([]unstructured.Unstructured, error) {
+	ul := &unstructured.UnstructuredList{}
+	ul.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := r.client.List(r.ctx, ul, ctrlclient.InNamespace(namespace), ctrlclient.MatchingLabelsSelector{Selector: selector}); err != nil {
 		return nil, err
 	}
-	return newObj, nil
+	return ul.Items, nil
 }
 
-func (r *Client) Update(ctx context.Context, obj *unstructured.Unstructured, options metav1.UpdateOptions) (*unstructured.Unstructured, error) {
-	m, err := r.getMapping(obj)
-	if err != nil {
+// getObject returns the current state of the object from the cluster.
+func (r *Client) getObject(ctx context.Context, u *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	u = u.DeepCopy()
+	if err := r.defaultNamespace(u, false); err != nil {
 		return nil, err
 	}
-	var newObj *unstructured.Unstructured
-	if m.Scope == meta.RESTScopeNamespace {
-		if obj.GetNamespace() == "" {
-			return nil, fmt.Errorf("expected namespace, got %s", obj.GetNamespace())
-		}
-		newObj, err = r.dc.Resource(m.Resource).Namespace(obj.GetNamespace()).Update(ctx, obj, options)
-	} else {
-		newObj, err = r.dc.Resource(m.Resource).Update(ctx, obj, options)
-	}
-	if err != nil {
+	newObj := &unstructured.Unstructured{}
+	newObj.SetGroupVersionKind(u.GroupVersionKind())
+	if err := r.Get(ctx, ctrlclient.ObjectKeyFromObject(u), newObj); err != nil {
 		return nil, err
 	}
 	return newObj, nil
 }
-
-func (r *Client) Delete(ctx context.Context, obj *unstructured.Unstructured, options metav1.DeleteOptions) error {
-	m, err := r.getMapping(obj)
-	if err != nil {
-		return err
-	}
-	if m.Scope == meta.RESTScopeNamespace {
-		if obj.GetNamespace() == "" {
-			return fmt.Errorf("expected namespace, got %s", obj.GetNamespace())
-		}
-		err = r.dc.Resource(m.Resource).Namespace(obj.GetNamespace()).Delete(ctx, obj.GetName(), options)
-	} else {
-		err = r.dc.Resource(m.Resource).Delete(ctx, obj.GetName(), options)
-	}
-	if err != nil {
-		return err
-	}
-	return nil
-}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var podGVK = schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+
+func newPod(namespace, name string, lbls map[string]string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(podGVK)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetLabels(lbls)
+	return u
+}
+
+func TestStatusLookup(t *testing.T) {
+	c := newTestClient(map[schema.GroupVersionKind]meta.RESTScope{podGVK: meta.RESTScopeNamespace},
+		[]runtime.Object{
+			newPod("default", "web-1", map[string]string{"app": "web"}),
+			newPod("default", "db-1", map[string]string{"app": "db"}),
+			newPod("other", "web-2", map[string]string{"app": "web"}),
+		}...)
+	lookup := &statusLookup{ctx: context.Background(), client: c}
+
+	pod, err := lookup.Get(podGVK, "default", "web-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "web-1", pod.GetName())
+
+	_, err = lookup.Get(podGVK, "default", "web-2")
+	assert.True(t, apierrors.IsNotFound(err))
+
+	pods, err := lookup.List(podGVK, "default", labels.SelectorFromSet(labels.Set{"app": "web"}))
+	assert.NoError(t, err)
+	names := []string{}
+	for _, p := range pods {
+		names = append(names, p.GetName())
+	}
+	assert.Equal(t, []string{"web-1"}, names)
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
-	"strings"
 	"time"
 
 	"github.com/henderiw/logger/log"
@@ -16,15 +15,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func resourceKubernetesManifest() *schema.Resource {
-	defaultTimout := 5 * time.Minute
+	defaultTimout := resourceTimeout
 	return &schema.Resource{
-		ReadContext:   resourceKubernetesManifestRead,
-		CreateContext: resourceKubernetesManifestCreate,
-		UpdateContext: resourceKubernetesManifestUpdate,
-		DeleteContext: resourceKubernetesManifestDelete,
+		ReadContext:   withWarnings(resourceKubernetesManifestRead),
+		CreateContext: withWarnings(resourceKubernetesManifestCreate),
+		UpdateContext: withWarnings(resourceKubernetesManifestUpdate),
+		DeleteContext: withDeleteWarnings(resourceKubernetesManifestDelete),
 		Timeouts: &schema.ResourceTimeout{
 			Create:  &defaultTimout,
 			Read:    &defaultTimout,
@@ -41,15 +41,30 @@ func resourceKubernetesManifestRead(ctx context.Context, obj *schema.ResourceObj
 		return nil, diag.FromErr(err)
 	}
 
-	newObj, err := client.Get(ctx, u, metav1.GetOptions{})
+	// a removed apiVersion is read with its replacement when it is converted
+	apiVersion := u.GetAPIVersion()
+	u, diags := client.checkDeprecatedAPI(ctx, u)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	newObj, err := client.getObject(ctx, u)
 	if err != nil {
-		return nil, diag.FromErr(err)
+		return nil, apiErrorDiags(u, err)
+	}
+	// the status is informational for a read, the failed checks explaining it are
+	// returned as warnings and not recorded in the state of the object
+	result, err := client.computeStatus(ctx, newObj)
+	if err != nil {
+		log.FromContext(ctx).Debug("cannot compute status", "err", err.Error())
 	}
+	client.defaults.strip(newObj, u)
+	newObj.SetAPIVersion(apiVersion)
 	b, err := json.Marshal(newObj)
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
-	return b, nil
+	return b, statusCheckDiags(newObj, result)
 }
 
 func resourceKubernetesManifestCreate(ctx context.Context, obj *schema.ResourceObject, meta interface{}) ([]byte, diag.Diagnostics) {
@@ -60,18 +75,58 @@ func resourceKubernetesManifestCreate(ctx context.Context, obj *schema.ResourceO
 		return nil, diag.FromErr(err)
 	}
 
-	var dryRun []string
-	if obj.IsDryRun() {
-		dryRun = []string{"All"}
+	// the state keeps the configured apiVersion of an object converted to the
+	// replacement of a removed apiVersion, such that it does not differ from the
+	// config on every plan
+	apiVersion := u.GetAPIVersion()
+	u, diags := client.checkDeprecatedAPI(ctx, u)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	if err := client.defaultNamespace(u, true); err != nil {
+		return nil, diag.FromErr(err)
 	}
 
-	newObj, err := client.Create(ctx, u, metav1.CreateOptions{DryRun: dryRun})
+	fieldValidation, err := client.getFieldValidation(u)
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
 
+	client.recordCRD(u)
+	if obj.IsDryRun() {
+		if b, diags, deferred := client.deferUnservedKind(ctx, u); deferred {
+			return b, diags
+		}
+		verbs := []string{"create", "get"}
+		if client.adoptExisting {
+			// an existing object is adopted with an update
+			verbs = append(verbs, "update")
+		}
+		if err := client.preflightAccess(ctx, u, verbs...); err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
+
+	newObj := u.DeepCopy()
+	if err := client.defaults.apply(newObj); err != nil {
+		return nil, diag.FromErr(err)
+	}
+	// the create fails with AlreadyExists, unless existing objects are adopted
+	w := client.writer(obj.IsDryRun(), fieldValidation)
+	if client.adoptExisting {
+		err = w.Apply(ctx, newObj, client.applyOpts...)
+	} else {
+		err = w.Create(ctx, newObj)
+	}
+	if err != nil {
+		return nil, apiErrorDiags(u, err)
+	}
+
 	// when dryrun we do not get the response from the system as we already got the data
 	if obj.IsDryRun() {
+		client.defaults.strip(newObj, u)
+		newObj.SetAPIVersion(apiVersion)
 		b, err := json.Marshal(newObj)
 		if err != nil {
 			return nil, diag.FromErr(err)
@@ -80,10 +135,15 @@ func resourceKubernetesManifestCreate(ctx context.Context, obj *schema.ResourceO
 	}
 
 	// when no dryrun, we get the response from the system by checking the status
-	newObj, err = getStatusWithRetries(ctx, client, u, false)
+	newObj, result, err := getStatusWithRetries(ctx, client, u, false)
 	if err != nil {
-		return nil, diag.FromErr(err)
+		diags := append(diag.FromErr(err), statusCheckDiags(u, result)...)
+		return nil, append(diags, client.warningEventDiags(ctx, u)...)
 	}
+	// the kinds of an established CRD are only known after a reset of the mapper
+	client.resetMapperForCRD(newObj)
+	client.defaults.strip(newObj, u)
+	newObj.SetAPIVersion(apiVersion)
 	b, err := json.Marshal(newObj)
 	if err != nil {
 		return nil, diag.FromErr(err)
@@ -107,18 +167,46 @@ func resourceKubernetesManifestUpdate(ctx context.Context, obj *schema.ResourceO
 		newu.SetResourceVersion(oldu.GetResourceVersion())
 	}
 
-	var dryRun []string
-	if obj.IsDryRun() {
-		dryRun = []string{"All"}
+	// the state keeps the configured apiVersion of a converted object
+	apiVersion := newu.GetAPIVersion()
+	newu, diags := client.checkDeprecatedAPI(ctx, newu)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	if err := client.defaultNamespace(newu, true); err != nil {
+		return nil, diag.FromErr(err)
 	}
 
-	newObj, err := client.Update(ctx, newu, metav1.UpdateOptions{DryRun: dryRun})
+	fieldValidation, err := client.getFieldValidation(newu)
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
 
+	client.recordCRD(newu)
+	if obj.IsDryRun() {
+		if b, diags, deferred := client.deferUnservedKind(ctx, newu); deferred {
+			return b, diags
+		}
+		if err := client.preflightAccess(ctx, newu, "update", "get"); err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
+
+	newObj := newu.DeepCopy()
+	if err := client.defaults.apply(newObj); err != nil {
+		return nil, diag.FromErr(err)
+	}
+	// the update fails with a Conflict when the object changed since the last
+	// observed state
+	if err := client.writer(obj.IsDryRun(), fieldValidation).Apply(ctx, newObj, client.applyOpts...); err != nil {
+		return nil, apiErrorDiags(newu, err)
+	}
+
 	// when dryrun we do not get the response from the system as we already got the data
 	if obj.IsDryRun() {
+		client.defaults.strip(newObj, newu)
+		newObj.SetAPIVersion(apiVersion)
 		b, err := json.Marshal(newObj)
 		if err != nil {
 			return nil, diag.FromErr(err)
@@ -127,10 +215,15 @@ func resourceKubernetesManifestUpdate(ctx context.Context, obj *schema.ResourceO
 	}
 
 	// when no dryrun, we get the response from the system by checking the status
-	newObj, err = getStatusWithRetries(ctx, client, newu, false)
+	newObj, result, err := getStatusWithRetries(ctx, client, newu, false)
 	if err != nil {
-		return nil, diag.FromErr(err)
+		diags := append(diag.FromErr(err), statusCheckDiags(newu, result)...)
+		return nil, append(diags, client.warningEventDiags(ctx, newu)...)
 	}
+	// the kinds of an established CRD are only known after a reset of the mapper
+	client.resetMapperForCRD(newObj)
+	client.defaults.strip(newObj, newu)
+	newObj.SetAPIVersion(apiVersion)
 
 	b, err := json.Marshal(newObj)
 	if err != nil {
@@ -147,24 +240,40 @@ func resourceKubernetesManifestDelete(ctx context.Context, obj *schema.ResourceO
 		return diag.FromErr(err)
 	}
 
-	if _, err := client.Get(ctx, u, metav1.GetOptions{}); err != nil {
-		if strings.Contains(err.Error(), "not found") {
+	// a removed apiVersion is deleted with its replacement when it is converted
+	u, diags := client.checkDeprecatedAPI(ctx, u)
+	if diags.HasError() {
+		return diags
+	}
+
+	if err := client.defaultNamespace(u, false); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if _, err := client.getObject(ctx, u); err != nil {
+		if apierrors.IsNotFound(err) {
 			return nil
 		}
-		return diag.FromErr(err)
+		return apiErrorDiags(u, err)
 	}
 
 	var dryRun []string
 	if obj.IsDryRun() {
 		dryRun = []string{"All"}
+		if err := client.preflightAccess(ctx, u, "delete", "get"); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
-	if err := client.Delete(ctx, u, metav1.DeleteOptions{DryRun: dryRun}); err != nil {
-		return diag.FromErr(err)
+	if err := client.Delete(ctx, u.DeepCopy(), &ctrlclient.DeleteOptions{DryRun: dryRun}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return apiErrorDiags(u, err)
 	}
 
-	if _, err := getStatusWithRetries(ctx, client, u, true); err != nil {
-		return diag.FromErr(err)
+	if _, _, err := getStatusWithRetries(ctx, client, u, true); err != nil {
+		return append(diag.FromErr(err), client.warningEventDiags(ctx, u)...)
 	}
 
 	return nil
@@ -175,13 +284,21 @@ const (
 	backoffFactor   float64       = 2
 	initialDelay    time.Duration = 1 * time.Second
 	initialGetDelay time.Duration = 500 * time.Millisecond
+	// resourceTimeout bounds the create and update of a resource, including
+	// the wait for its status
+	resourceTimeout time.Duration = 5 * time.Minute
 )
 
 // getStatusWithRetries tries to get Status with exponential backoff.
-// maxRetries: the maximum number of retries before giving up.
+// maxRetries: the maximum number of retries before giving up, unless a progress
+// deadline is configured for the kind of the resource. In that case the status is
+// polled until the resourceTimeout, such that the progress deadline reports the
+// resource as failed when it makes no progress.
 // backoffFactor: the factor by which the backoff duration is exponentially increased.
 // initialDelay: the initial delay before the first retry.
-func getStatusWithRetries(ctx context.Context, client *Client, u *unstructured.Unstructured, delete bool) (*unstructured.Unstructured, error) {
+// The last computed status is returned, also on error, such that the checks
+// explaining the status can be reported.
+func getStatusWithRetries(ctx context.Context, client *Client, u *unstructured.Unstructured, delete bool) (*unstructured.Unstructured, *status.Result, error) {
 	//log := log.FromContext(ctx)
 	gvk := u.GetObjectKind().GroupVersionKind().String()
 	nsn := types.NamespacedName{Namespace: u.GetNamespace(), Name: u.GetName()}.String()
@@ -189,17 +306,27 @@ func getStatusWithRetries(ctx context.Context, client *Client, u *unstructured.U
 	// otherwise we might conclude the reconcile is ready
 	// while the status is not yet updated
 	time.Sleep(initialGetDelay)
+	stop := time.Now().Add(resourceTimeout)
+	_, hasDeadline := client.progressDeadlines[u.GroupVersionKind().GroupKind()]
+	pollUntilTimeout := hasDeadline && !delete
+	var result *status.Result
 	var err error
-	for attempt := 0; attempt < maxRetries; attempt++ {
+	for attempt := 0; attempt < maxRetries || (pollUntilTimeout && time.Now().Before(stop)); attempt++ {
 		// get the resource
-		newObj, cont, err := getStatus(ctx, client, u, delete, attempt)
+		var newObj *unstructured.Unstructured
+		var cont bool
+		newObj, result, cont, err = getStatus(ctx, client, u, delete, attempt)
 		if !cont {
-			return newObj, err
+			return newObj, result, err
 		}
 
-		// Calculate the next backoff delay
-		backoff := float64(initialDelay) * math.Pow(backoffFactor, float64(attempt))
+		// Calculate the next backoff delay, it is capped at the delay of the last
+		// retry when polling until the timeout
+		backoff := float64(initialDelay) * math.Pow(backoffFactor, float64(min(attempt, maxRetries-1)))
 		backoffDuration := time.Duration(backoff)
+		if pollUntilTimeout {
+			backoffDuration = min(backoffDuration, time.Until(stop))
+		}
 
 		fmt.Printf("getStatus gvk %s nsn %s , retrying in %v... (Attempt %d/%d)\n",
 			gvk,
@@ -212,45 +339,65 @@ func getStatusWithRetries(ctx context.Context, client *Client, u *unstructured.U
 		// Wait for the backoff duration before retrying
 		time.Sleep(backoffDuration)
 	}
-	return nil, fmt.Errorf("getStatus gvk %s nsn %s after %d retries: %w", gvk, nsn, maxRetries, err)
+	if pollUntilTimeout {
+		return nil, result, fmt.Errorf("getStatus gvk %s nsn %s timed out after %s: %w", gvk, nsn, resourceTimeout, err)
+	}
+	return nil, result, fmt.Errorf("getStatus gvk %s nsn %s after %d retries: %w", gvk, nsn, maxRetries, err)
 }
 
-// getStatus gets the status of the object and returns the object if found, a boolean indicating continue true/false
-// and an error code
-func getStatus(ctx context.Context, client *Client, u *unstructured.Unstructured, delete bool, attempt int) (*unstructured.Unstructured, bool, error) {
+// getStatus gets the status of the object and returns the object if found, its computed status, a boolean
+// indicating continue true/false and an error code
+func getStatus(ctx context.Context, client *Client, u *unstructured.Unstructured, delete bool, attempt int) (*unstructured.Unstructured, *status.Result, bool, error) {
 	log := log.FromContext(ctx)
-	newObj, err := client.Get(ctx, u, metav1.GetOptions{})
+	newObj, err := client.getObject(ctx, u)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			if delete {
 				// success delete
-				return nil, false, nil
+				return nil, status.NotFound(), false, nil
 			}
 			// we should continue
-			return nil, true, nil
+			return nil, status.NotFound(), true, err
 		}
+		// transient errors are retried by the client, the others are permanent
 		log.Error("cannot get object", "err", err)
-		return nil, true, err
+		return nil, nil, false, err
 	}
-	result, err := status.Compute(newObj)
+	result, err := client.computeStatus(ctx, newObj)
 	if err != nil {
 		log.Error("cannot get object", "err", err)
-		return newObj, true, err
+		return newObj, nil, true, err
 	}
 	if result.Status == metav1.ConditionFalse {
 		if result.Reason == status.ReasonFailed {
 			err := fmt.Errorf("failed: %s", result.Message)
 			log.Error(err.Error())
-			return newObj, false, err
+			return newObj, result, false, err
 		}
-		return newObj, true, nil
+		return newObj, result, true, nil
 	}
-	if result.Reason == status.ReasonNoStatusInfo && attempt < maxRetries-2 {
+	if (result.Reason == status.ReasonNoStatusInfo || result.Reason == status.ReasonUnknown) && attempt < maxRetries-2 {
 		// continue since we expect status by default - we assume status field will
 		// come, so hence we retry maxRetries -2 (which is 4 times), the 5th time we
 		// just report ok as we did not get status for some time.
-		return newObj, true, nil
+		// An unknown status is treated the same way as it might become known.
+		return newObj, result, true, nil
 	}
 	// success (update/create)
-	return newObj, false, nil
+	return newObj, result, false, nil
+}
+
+// statusCheckDiags returns the failed checks explaining the status of the object as
+// warning diagnostics, such that it is clear why the object did not become ready.
+func statusCheckDiags(u *unstructured.Unstructured, result *status.Result) diag.Diagnostics {
+	if result == nil {
+		return nil
+	}
+	var diags diag.Diagnostics
+	for _, check := range result.FailedChecks() {
+		diags = append(diags, diag.DiagWarnfWithContext(
+			fmt.Sprintf("%s/%s", u.GetKind(), u.GetName()),
+			"check %s", check.String()).Get())
+	}
+	return diags
 }
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	sdkschema "github.com/kform-dev/kform-sdk-go/pkg/schema"
+	"github.com/kform-providers/kubernetes/provider/kstatus/status"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var configMapGVK = schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+func newConfigMap(name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(configMapGVK)
+	u.SetNamespace("default")
+	u.SetName(name)
+	return u
+}
+
+func TestGetStatusNotFound(t *testing.T) {
+	c := newTestClient(map[schema.GroupVersionKind]meta.RESTScope{configMapGVK: meta.RESTScopeNamespace})
+
+	// the object is expected to appear on create and update
+	obj, result, cont, err := getStatus(context.Background(), c, newConfigMap("missing"), false, 0)
+	assert.Nil(t, obj)
+	assert.Equal(t, status.NotFound(), result)
+	assert.True(t, cont)
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// a missing object completes the delete
+	obj, result, cont, err = getStatus(context.Background(), c, newConfigMap("missing"), true, 0)
+	assert.Nil(t, obj)
+	assert.Equal(t, status.NotFound(), result)
+	assert.False(t, cont)
+	assert.NoError(t, err)
+}
+
+func TestCreateExisting(t *testing.T) {
+	cases := map[string]struct {
+		adoptExisting bool
+		err           bool
+	}{
+		"AlreadyExists": {
+			err: true,
+		},
+		"Adopted": {
+			adoptExisting: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(map[schema.GroupVersionKind]meta.RESTScope{configMapGVK: meta.RESTScopeNamespace}, newConfigMap("web"))
+			c.defaults = &objectDefaults{}
+			c.adoptExisting = tc.adoptExisting
+
+			b, err := json.Marshal(newConfigMap("web"))
+			assert.NoError(t, err)
+			_, diags := resourceKubernetesManifestCreate(context.Background(), &sdkschema.ResourceObject{Obj: b, DryRun: true}, c)
+			assert.Equal(t, tc.err, diags.HasError())
+		})
+	}
+}
+
+func TestReadStatusChecks(t *testing.T) {
+	widget := newWidget()
+	widget.SetGeneration(2)
+	assert.NoError(t, unstructured.SetNestedField(widget.Object, int64(1), "status", "observedGeneration"))
+	c := newTestClient(map[schema.GroupVersionKind]meta.RESTScope{widget.GroupVersionKind(): meta.RESTScopeNamespace}, widget)
+	c.defaults = &objectDefaults{}
+	c.statusOpts = []status.Option{status.WithExplain(true)}
+
+	b, err := json.Marshal(newWidget())
+	assert.NoError(t, err)
+	b, diags := resourceKubernetesManifestRead(context.Background(), &sdkschema.ResourceObject{Obj: b}, c)
+	assert.False(t, diags.HasError())
+	// the failed checks are returned as warnings, not recorded in the state
+	assert.Len(t, diags, 1)
+	assert.Equal(t, `check observedGeneration: observed "1", expected "2"`, diags[0].GetDetail())
+	u := &unstructured.Unstructured{}
+	assert.NoError(t, json.Unmarshal(b, u))
+	assert.Empty(t, u.GetAnnotations())
+}
@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/kform-dev/kform-sdk-go/pkg/diag"
+	"github.com/kform-dev/kform-sdk-go/pkg/schema"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+)
+
+// warningRecorder is a rest.WarningHandler collecting the warnings the API server
+// returned for the requests of a resource operation, e.g. for deprecated APIs,
+// unknown fields or policy audits.
+type warningRecorder struct {
+	m        sync.Mutex
+	warnings []string
+}
+
+var _ rest.WarningHandler = &warningRecorder{}
+
+type warningRecorderKey struct{}
+
+// withWarningRecorder returns a context recording the warnings of the requests
+// made with the context.
+func withWarningRecorder(ctx context.Context) (context.Context, *warningRecorder) {
+	rec := &warningRecorder{}
+	return context.WithValue(ctx, warningRecorderKey{}, rec), rec
+}
+
+func warningRecorderFrom(ctx context.Context) *warningRecorder {
+	rec, _ := ctx.Value(warningRecorderKey{}).(*warningRecorder)
+	return rec
+}
+
+// HandleWarningHeader records the warning, only warnings with code 299 are recorded
+// as defined in https://k8s.io/enhancements/keps/sig-api-machinery/1693-warnings
+func (r *warningRecorder) HandleWarningHeader(code int, _ string, text string) {
+	if code != 299 || len(text) == 0 {
+		return
+	}
+	r.record(fmt.Sprintf("api server warning: %s", text))
+}
+
+func (r *warningRecorder) record(warning string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.warnings = append(r.warnings, warning)
+}
+
+// recordWarning records a warning of the provider for the resource operation of
+// the context, it is returned once per run like the warnings of the API server.
+func recordWarning(ctx context.Context, format string, a ...any) {
+	if rec := warningRecorderFrom(ctx); rec != nil {
+		rec.record(fmt.Sprintf(format, a...))
+	}
+}
+
+func (r *warningRecorder) list() []string {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return append([]string(nil), r.warnings...)
+}
+
+// warningTransport passes the Warning headers of the responses to the warningRecorder
+// of the request context. The rest.WarningHandler of the rest config has no access
+// to the request, so it cannot attribute the warnings to a resource operation.
+type warningTransport struct {
+	rt http.RoundTripper
+}
+
+func newWarningTransport(rt http.RoundTripper) http.RoundTripper {
+	return &warningTransport{rt: rt}
+}
+
+func (r *warningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.rt.RoundTrip(req)
+	if resp == nil {
+		return resp, err
+	}
+	rec := warningRecorderFrom(req.Context())
+	if rec == nil {
+		return resp, err
+	}
+	// malformed headers are ignored, the valid warnings are still returned
+	warnings, _ := utilnet.ParseWarningHeaders(resp.Header["Warning"])
+	for _, w := range warnings {
+		rec.HandleWarningHeader(w.Code, w.Agent, w.Text)
+	}
+	return resp, err
+}
+
+// warningDiags returns the warnings recorded for the operation on the object of
+// the context as warning diagnostics. A warning is returned once per run, as e.g.
+// the deprecation warning of an API is returned for all objects of the API.
+func (r *Client) warningDiags(ctx string, rec *warningRecorder) diag.Diagnostics {
+	r.m.Lock()
+	defer r.m.Unlock()
+	var diags diag.Diagnostics
+	for _, w := range rec.list() {
+		if _, ok := r.warnings[w]; ok {
+			continue
+		}
+		r.warnings[w] = struct{}{}
+		diags = append(diags, diag.DiagWarnfWithContext(ctx, "%s", w).Get())
+	}
+	return diags
+}
+
+// withWarnings returns the resource operation with the warnings of the API server
+// for its requests appended to its diagnostics.
+func withWarnings(fn func(context.Context, *schema.ResourceObject, interface{}) ([]byte, diag.Diagnostics)) func(context.Context, *schema.ResourceObject, interface{}) ([]byte, diag.Diagnostics) {
+	return func(ctx context.Context, obj *schema.ResourceObject, meta interface{}) ([]byte, diag.Diagnostics) {
+		ctx, rec := withWarningRecorder(ctx)
+		b, diags := fn(ctx, obj, meta)
+		return b, append(diags, operationWarningDiags(obj, meta, rec)...)
+	}
+}
+
+// withDeleteWarnings is withWarnings for the delete operation.
+func withDeleteWarnings(fn func(context.Context, *schema.ResourceObject, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceObject, interface{}) diag.Diagnostics {
+	return func(ctx context.Context, obj *schema.ResourceObject, meta interface{}) diag.Diagnostics {
+		ctx, rec := withWarningRecorder(ctx)
+		diags := fn(ctx, obj, meta)
+		return append(diags, operationWarningDiags(obj, meta, rec)...)
+	}
+}
+
+func operationWarningDiags(obj *schema.ResourceObject, meta interface{}, rec *warningRecorder) diag.Diagnostics {
+	client, ok := meta.(*Client)
+	if !ok {
+		return nil
+	}
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(obj.GetObject(), u); err != nil {
+		// the object is only used as context of the diagnostics, the warnings
+		// are still returned when the object cannot be decoded
+		return client.warningDiags("", rec)
+	}
+	return client.warningDiags(fmt.Sprintf("%s/%s", u.GetKind(), u.GetName()), rec)
+}
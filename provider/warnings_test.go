@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/kform-dev/kform-sdk-go/pkg/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestWarningTransport(t *testing.T) {
+	rt := newWarningTransport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Warning": []string{
+			`299 - "policy/v1beta1 PodDisruptionBudget is deprecated"`,
+			`299 - "unknown field \"spec.replica\""`,
+			// only warnings with code 299 are recorded
+			`199 - "miscellaneous warning"`,
+			// malformed headers are ignored
+			`malformed`,
+		}}}, nil
+	}))
+
+	cases := map[string]struct {
+		record   bool
+		warnings []string
+	}{
+		"Recorded": {
+			record: true,
+			warnings: []string{
+				"api server warning: policy/v1beta1 PodDisruptionBudget is deprecated",
+				`api server warning: unknown field "spec.replica"`,
+			},
+		},
+		// the requests outside of a resource operation are not recorded
+		"NoRecorder": {},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			var rec *warningRecorder
+			if tc.record {
+				ctx, rec = withWarningRecorder(ctx)
+			}
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://127.0.0.1:6443/api/v1/namespaces", nil)
+			assert.NoError(t, err)
+			resp, err := rt.RoundTrip(req)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			if rec != nil {
+				assert.Equal(t, tc.warnings, rec.list())
+			}
+		})
+	}
+}
+
+func TestWarningDiags(t *testing.T) {
+	c := &Client{warnings: map[string]struct{}{}}
+
+	_, rec := withWarningRecorder(context.Background())
+	rec.record("api server warning: policy/v1beta1 PodDisruptionBudget is deprecated")
+	rec.record("api server warning: unknown field \"spec.replica\"")
+	diags := c.warningDiags("ConfigMap/a", rec)
+	assert.Len(t, diags, 2)
+	assert.Equal(t, "api server warning: policy/v1beta1 PodDisruptionBudget is deprecated", diags[0].GetDetail())
+	assert.Equal(t, "ConfigMap/a", diags[0].GetContext())
+
+	// a warning is returned once per run
+	_, rec = withWarningRecorder(context.Background())
+	rec.record("api server warning: policy/v1beta1 PodDisruptionBudget is deprecated")
+	rec.record("api server warning: spec.template: deprecated field")
+	diags = c.warningDiags("ConfigMap/b", rec)
+	assert.Len(t, diags, 1)
+	assert.Equal(t, "api server warning: spec.template: deprecated field", diags[0].GetDetail())
+	assert.Equal(t, "ConfigMap/b", diags[0].GetContext())
+}
+
+func TestOperationWarningDiags(t *testing.T) {
+	cases := map[string]struct {
+		obj     []byte
+		context string
+	}{
+		"Object": {
+			obj:     []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"a"}}`),
+			context: "ConfigMap/a",
+		},
+		// the warnings are returned without context when the object cannot be decoded
+		"InvalidObject": {
+			obj: []byte(`{"apiVersion":"v1"`),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{warnings: map[string]struct{}{}}
+			_, rec := withWarningRecorder(context.Background())
+			rec.record("api server warning: spec.template: deprecated field")
+
+			diags := operationWarningDiags(&schema.ResourceObject{Obj: tc.obj}, c, rec)
+			assert.False(t, diags.HasError())
+			assert.Len(t, diags, 1)
+			assert.Equal(t, "api server warning: spec.template: deprecated field", diags[0].GetDetail())
+			assert.Equal(t, tc.context, diags[0].GetContext())
+		})
+	}
+}